@@ -0,0 +1,29 @@
+package clients
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+)
+
+//ErrJobNotFound is returned when the continuous integration job of a configuration does not exist.
+var ErrJobNotFound = errors.New("job not found")
+
+//CIBuilderClient represents a continuous integration backend.
+//It creates and deletes the jobs of the configured repositories and runs their builds.
+type CIBuilderClient interface {
+	CreateJob(config *models.Configuration) error
+	DeleteJob(config *models.Configuration) error
+	TriggerBuild(config *models.Configuration, branch string, sha string) (*models.BuildStatus, error)
+	GetBuildStatus(config *models.Configuration, buildID string) (*models.BuildStatus, error)
+}
+
+//NewCIBuilderClient initializes the client of the configured continuous integration backend.
+func NewCIBuilderClient() CIBuilderClient {
+	switch configs.GetCIBuilder() {
+	case "ci-proxy":
+		return NewCIProxyClient()
+	default:
+		return NewJenkinsClient()
+	}
+}
@@ -0,0 +1,157 @@
+package clients
+
+// Builder client, connects configurations API with the CI proxy API
+// and implements the necessary functions to
+// create and delete jobs necessary for the execution of release process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/mercadolibre/golang-restclient/rest"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type ciProxyClient struct {
+	Client Client
+}
+
+func NewCIProxyClient() CIBuilderClient {
+	hs := make(http.Header)
+	hs.Set("cache-control", "no-cache")
+
+	return &ciProxyClient{
+		Client: &client{
+			RestClient: &rest.RequestBuilder{
+				BaseURL:        configs.GetCIProxyBaseURL(),
+				Timeout:        2 * time.Second,
+				Headers:        hs,
+				ContentType:    rest.JSON,
+				DisableCache:   true,
+				DisableTimeout: false,
+			},
+		},
+	}
+}
+
+//CreateJob creates the continuous integration job of the repository.
+//This perform a POST request to CI proxy api
+func (c *ciProxyClient) CreateJob(config *models.Configuration) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	body := map[string]interface{}{
+		"repository_name":  *config.RepositoryName,
+		"repository_owner": *config.RepositoryOwner,
+		"workflow_type":    config.WorkflowType,
+	}
+
+	response := c.Client.Post("/jobs", body)
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return errors.New(fmt.Sprintf("error creating ci job - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//DeleteJob deletes the continuous integration job of the repository.
+//This perform a DELETE request to CI proxy api
+func (c *ciProxyClient) DeleteJob(config *models.Configuration) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	response := c.Client.Delete(fmt.Sprintf("/jobs/%s/%s", *config.RepositoryOwner, *config.RepositoryName))
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusNoContent {
+		if response.StatusCode() == http.StatusNotFound {
+			return ErrJobNotFound
+		}
+		return errors.New(fmt.Sprintf("error deleting ci job - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//TriggerBuild starts a build of a repository branch at the given commit.
+//This perform a POST request to CI proxy api
+func (c *ciProxyClient) TriggerBuild(config *models.Configuration, branch string, sha string) (*models.BuildStatus, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || branch == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"branch": branch,
+		"sha":    sha,
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/jobs/%s/%s/builds", *config.RepositoryOwner, *config.RepositoryName), body)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		if response.StatusCode() == http.StatusNotFound {
+			return nil, ErrJobNotFound
+		}
+		return nil, errors.New(fmt.Sprintf("error triggering ci build - status: %d", response.StatusCode()))
+	}
+
+	var build models.BuildStatus
+	if err := json.Unmarshal(response.Bytes(), &build); err != nil {
+		return nil, errors.New("error binding ci build response")
+	}
+
+	return &build, nil
+}
+
+//GetBuildStatus gets the status of a build of the repository.
+//This perform a GET request to CI proxy api
+func (c *ciProxyClient) GetBuildStatus(config *models.Configuration, buildID string) (*models.BuildStatus, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || buildID == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Get(fmt.Sprintf("/jobs/%s/%s/builds/%s", *config.RepositoryOwner, *config.RepositoryName, url.PathEscape(buildID)))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			return nil, errors.New("build not found")
+		}
+		return nil, errors.New(fmt.Sprintf("error getting ci build - status: %d", response.StatusCode()))
+	}
+
+	var build models.BuildStatus
+	if err := json.Unmarshal(response.Bytes(), &build); err != nil {
+		return nil, errors.New("error binding ci build response")
+	}
+
+	return &build, nil
+}
@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"encoding/json"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mercadolibre/golang-restclient/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCIProxyClient(server *httptest.Server) *ciProxyClient {
+	return &ciProxyClient{
+		Client: &client{
+			RestClient: &rest.RequestBuilder{
+				BaseURL:      server.URL,
+				ContentType:  rest.JSON,
+				DisableCache: true,
+			},
+		},
+	}
+}
+
+func Test_ciProxyClient_Builds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/herbal828/ci_cd-api/builds":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["branch"] != "develop" || body["sha"] != "abc123" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"42","state":"pending"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/jobs/herbal828/ci_cd-api/builds/42":
+			w.Write([]byte(`{"id":"42","state":"success","log_url":"http://ci/42/log"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestCIProxyClient(server)
+	config := &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+
+	tests := []struct {
+		name      string
+		run       func() (*models.BuildStatus, error)
+		wantState string
+		wantErr   string
+	}{
+		{
+			name:      "test - trigger build",
+			run:       func() (*models.BuildStatus, error) { return c.TriggerBuild(config, "develop", "abc123") },
+			wantState: models.BuildStatePending,
+		},
+		{
+			name:      "test - get build status",
+			run:       func() (*models.BuildStatus, error) { return c.GetBuildStatus(config, "42") },
+			wantState: models.BuildStateSuccess,
+		},
+		{
+			name:    "test - build not found",
+			run:     func() (*models.BuildStatus, error) { return c.GetBuildStatus(config, "43") },
+			wantErr: "build not found",
+		},
+		{
+			name: "test - invalid params",
+			run: func() (*models.BuildStatus, error) {
+				return c.TriggerBuild(&models.Configuration{}, "develop", "abc123")
+			},
+			wantErr: "invalid body params",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build, err := tt.run()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantState, build.State)
+		})
+	}
+}
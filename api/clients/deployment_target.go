@@ -0,0 +1,211 @@
+package clients
+
+// Deployment targets, deliver the deployments of the environments
+// to the systems which perform them
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/mercadolibre/golang-restclient/rest"
+	"net/http"
+	"time"
+)
+
+//DeploymentTarget represents a system which performs the deployments of an environment.
+type DeploymentTarget interface {
+	Deploy(config *models.Configuration, deployment *models.Deployment) error
+}
+
+//NewDeploymentTarget initializes the deployment target of the environment.
+//The secret of the target is resolved from its reference, see configs.GetDeploymentTargetSecret.
+//It returns nil for the environments deployed by the CD backends listening to the Github deployment events.
+func NewDeploymentTarget(env *models.Environment) (DeploymentTarget, error) {
+	switch env.GetTargetType() {
+	case models.EnvironmentTargetGithub:
+		return nil, nil
+	case models.EnvironmentTargetWebhook:
+		if env.TargetURL == "" {
+			return nil, errors.New("invalid webhook target")
+		}
+		return &webhookTarget{
+			URL:    env.TargetURL,
+			Secret: configs.GetDeploymentTargetSecret(env.TargetSecretRef),
+		}, nil
+	case models.EnvironmentTargetKubernetes:
+		if env.TargetURL == "" || env.TargetNamespace == "" || env.TargetDeployment == "" || env.TargetContainer == "" || env.TargetImage == "" {
+			return nil, errors.New("invalid kubernetes target")
+		}
+		return &kubernetesTarget{
+			APIServerURL: env.TargetURL,
+			Token:        configs.GetDeploymentTargetSecret(env.TargetSecretRef),
+			Namespace:    env.TargetNamespace,
+			Deployment:   env.TargetDeployment,
+			Container:    env.TargetContainer,
+			Image:        env.TargetImage,
+		}, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown deployment target %s", env.TargetType))
+	}
+}
+
+//webhookTarget delivers the deployments to an HTTP endpoint.
+//The body is signed with the target secret using HMAC-SHA256, so the receiver can verify its origin.
+type webhookTarget struct {
+	URL    string
+	Secret string
+}
+
+//WebhookTargetPayload is the body delivered to the webhook deployment targets.
+type WebhookTargetPayload struct {
+	DeploymentID *uint64 `json:"deployment_id"`
+	Repository   string  `json:"repository"`
+	Environment  string  `json:"environment"`
+	Ref          string  `json:"ref"`
+	Sha          string  `json:"sha"`
+	Rollback     bool    `json:"rollback"`
+}
+
+//SignWebhookTargetPayload returns the value of the X-Signature-256 header for the body.
+func SignWebhookTargetPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//Deploy delivers the deployment to the webhook.
+//This perform a POST request to the target URL
+func (t *webhookTarget) Deploy(config *models.Configuration, deployment *models.Deployment) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	body, err := json.Marshal(WebhookTargetPayload{
+		DeploymentID: deployment.ID,
+		Repository:   fmt.Sprintf("%s/%s", *config.RepositoryOwner, *config.RepositoryName),
+		Environment:  deployment.Environment,
+		Ref:          deployment.Ref,
+		Sha:          deployment.Sha,
+		Rollback:     deployment.RollbackToID != nil,
+	})
+
+	if err != nil {
+		return errors.New("error building webhook target payload")
+	}
+
+	//The signature changes with every body, so the headers are set per request
+	hs := make(http.Header)
+	hs.Set("Content-Type", "application/json")
+	hs.Set("X-Signature-256", SignWebhookTargetPayload(t.Secret, body))
+
+	c := &client{
+		RestClient: &rest.RequestBuilder{
+			Timeout:      5 * time.Second,
+			Headers:      hs,
+			ContentType:  rest.BYTES,
+			DisableCache: true,
+		},
+	}
+
+	response := c.Post(t.URL, body)
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() < http.StatusOK || response.StatusCode() >= http.StatusMultipleChoices {
+		return errors.New(fmt.Sprintf("error delivering deployment to webhook - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//kubernetesTarget updates the image of a container of a Kubernetes Deployment through the API server.
+//The image is tagged with the deployed commit.
+type kubernetesTarget struct {
+	APIServerURL string
+	Token        string
+	Namespace    string
+	Deployment   string
+	Container    string
+	Image        string
+	Client       Client
+}
+
+//Deploy patches the Kubernetes Deployment with the image of the deployed commit.
+//This perform a PATCH request to Kubernetes API server
+func (t *kubernetesTarget) Deploy(config *models.Configuration, deployment *models.Deployment) error {
+
+	tag := deployment.Sha
+	if tag == "" {
+		tag = deployment.Ref
+	}
+
+	if tag == "" {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]string{
+						{
+							"name":  t.Container,
+							"image": fmt.Sprintf("%s:%s", t.Image, tag),
+						},
+					},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		return errors.New("error building kubernetes patch")
+	}
+
+	response := t.getClient().Patch(fmt.Sprintf("/apis/apps/v1/namespaces/%s/deployments/%s", t.Namespace, t.Deployment), patch)
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			return errors.New("kubernetes deployment not found")
+		}
+		return errors.New(fmt.Sprintf("error patching kubernetes deployment - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+func (t *kubernetesTarget) getClient() Client {
+	if t.Client != nil {
+		return t.Client
+	}
+
+	hs := make(http.Header)
+	hs.Set("Authorization", "Bearer "+t.Token)
+	hs.Set("Content-Type", "application/strategic-merge-patch+json")
+
+	t.Client = &client{
+		RestClient: &rest.RequestBuilder{
+			BaseURL:      t.APIServerURL,
+			Timeout:      5 * time.Second,
+			Headers:      hs,
+			ContentType:  rest.BYTES,
+			DisableCache: true,
+		},
+	}
+
+	return t.Client
+}
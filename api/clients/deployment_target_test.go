@@ -0,0 +1,190 @@
+package clients
+
+import (
+	"encoding/json"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_webhookTarget_Deploy(t *testing.T) {
+	var received WebhookTargetPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("X-Signature-256") != SignWebhookTargetPayload("s3cr3t", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	config := &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+	deployment := &models.Deployment{Environment: "staging", Ref: "develop", Sha: "abc123"}
+
+	os.Setenv("DEPLOYMENT_TARGET_SECRET_STAGING_HOOK", "s3cr3t")
+	os.Setenv("DEPLOYMENT_TARGET_SECRET_OTHER_HOOK", "other")
+	defer os.Unsetenv("DEPLOYMENT_TARGET_SECRET_STAGING_HOOK")
+	defer os.Unsetenv("DEPLOYMENT_TARGET_SECRET_OTHER_HOOK")
+
+	tests := []struct {
+		name      string
+		secretRef string
+		wantErr   bool
+	}{
+		{
+			name:      "test - signed delivery",
+			secretRef: "staging-hook",
+		},
+		{
+			name:      "test - wrong secret",
+			secretRef: "other-hook",
+			wantErr:   true,
+		},
+		{
+			name:      "test - unknown secret reference",
+			secretRef: "missing-hook",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := NewDeploymentTarget(&models.Environment{
+				TargetType:      models.EnvironmentTargetWebhook,
+				TargetURL:       server.URL,
+				TargetSecretRef: tt.secretRef,
+			})
+			assert.NoError(t, err)
+
+			err = target.Deploy(config, deployment)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "herbal828/ci_cd-api", received.Repository)
+			assert.Equal(t, "abc123", received.Sha)
+		})
+	}
+}
+
+//fakeKubernetes is a minimal Kubernetes API server which keeps the image of the containers of a Deployment.
+type fakeKubernetes struct {
+	images map[string]string
+}
+
+func (f *fakeKubernetes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer k8s-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPatch || r.URL.Path != "/apis/apps/v1/namespaces/frontend/deployments/ci-cd-api" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != "application/strategic-merge-patch+json" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var patch struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Name  string `json:"name"`
+						Image string `json:"image"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for _, c := range patch.Spec.Template.Spec.Containers {
+		f.images[c.Name] = c.Image
+	}
+	w.Write([]byte(`{"kind":"Deployment"}`))
+}
+
+func Test_kubernetesTarget_Deploy(t *testing.T) {
+	fake := &fakeKubernetes{images: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	os.Setenv("DEPLOYMENT_TARGET_SECRET_K8S_PRODUCTION", "k8s-token")
+	defer os.Unsetenv("DEPLOYMENT_TARGET_SECRET_K8S_PRODUCTION")
+
+	config := &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+	env := models.Environment{
+		TargetType:       models.EnvironmentTargetKubernetes,
+		TargetURL:        server.URL,
+		TargetSecretRef:  "k8s-production",
+		TargetNamespace:  "frontend",
+		TargetDeployment: "ci-cd-api",
+		TargetContainer:  "api",
+		TargetImage:      "registry.herbal828.com/ci-cd-api",
+	}
+
+	tests := []struct {
+		name       string
+		deployment string
+		wantErr    string
+	}{
+		{
+			name:       "test - patch the image tag",
+			deployment: "ci-cd-api",
+		},
+		{
+			name:       "test - deployment not found",
+			deployment: "missing",
+			wantErr:    "kubernetes deployment not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := env
+			e.TargetDeployment = tt.deployment
+			target, err := NewDeploymentTarget(&e)
+			assert.NoError(t, err)
+
+			err = target.Deploy(config, &models.Deployment{Ref: "master", Sha: "abc123"})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "registry.herbal828.com/ci-cd-api:abc123", fake.images["api"])
+		})
+	}
+}
+
+func TestNewDeploymentTarget(t *testing.T) {
+	target, err := NewDeploymentTarget(&models.Environment{})
+	assert.NoError(t, err)
+	assert.Nil(t, target)
+
+	_, err = NewDeploymentTarget(&models.Environment{TargetType: models.EnvironmentTargetKubernetes})
+	assert.EqualError(t, err, "invalid kubernetes target")
+
+	_, err = NewDeploymentTarget(&models.Environment{TargetType: "ftp"})
+	assert.Error(t, err)
+}
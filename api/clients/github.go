@@ -1,8 +1,8 @@
 package clients
 
-// Builder client, connects configurations API with the CI proxy API
-// and implements the necessary functions to
-// create and delete jobs necessary for the execution of release process
+// Github client, connects configurations API with the Github API
+// and implements the necessary functions to apply the workflows
+// and execute the release process on the repositories
 
 import (
 	"encoding/json"
@@ -12,14 +12,48 @@ import (
 	"github.com/herbal828/ci_cd-api/api/models"
 	"github.com/mercadolibre/golang-restclient/rest"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+var (
+	//ErrWebhookNotFound is returned when the repository webhook of a configuration does not exist on Github.
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	//ErrInvalidToken is returned when Github does not accept the token of the client.
+	ErrInvalidToken = errors.New("invalid github token")
+
+	//ErrPullRequestNotFound is returned when a pull request does not exist on Github.
+	ErrPullRequestNotFound = errors.New("pull request not found")
+)
+
 type GithubClient interface {
 	GetBranchInformation(config *models.Configuration, branchName string) (*models.GetBranchResponse, error)
+	CreateBranch(config *models.Configuration, branchConfig *models.Branch, sha string) error
+	DeleteBranch(config *models.Configuration, name string) error
 	CreateGithubRef(config *models.Configuration, branchConfig *models.Branch, workflowConfig *models.WorkflowConfig) error
 	ProtectBranch(config *models.Configuration, branchConfig *models.Branch) error
 	SetDefaultBranch(config *models.Configuration, workflowConfig *models.WorkflowConfig) error
+	CreateWebhook(config *models.Configuration) (*models.Webhook, error)
+	GetWebhook(config *models.Configuration) (*models.Webhook, error)
+	DeleteWebhook(config *models.Configuration) error
+	CreatePullRequest(config *models.Configuration, head string, base string, title string, body string) (*models.PullRequest, error)
+	GetPullRequest(config *models.Configuration, number int) (*models.PullRequest, error)
+	FindPullRequest(config *models.Configuration, head string, base string) (*models.PullRequest, error)
+	MergePullRequest(config *models.Configuration, number int, commitTitle string) (*models.MergePullRequestResponse, error)
+	GetCombinedStatus(config *models.Configuration, ref string) (*models.CombinedStatus, error)
+	CreateStatus(config *models.Configuration, sha string, status *models.CommitStatus) error
+	ListCheckRuns(config *models.Configuration, sha string, name string) ([]models.CheckRun, error)
+	CreateCheckRun(config *models.Configuration, run *models.CheckRun) (*models.CheckRun, error)
+	UpdateCheckRun(config *models.Configuration, id int64, run *models.CheckRun) (*models.CheckRun, error)
+	CreateDeployment(config *models.Configuration, ref string, environment string, description string) (*models.GithubDeployment, error)
+	CreateDeploymentStatus(config *models.Configuration, id int64, status *models.GithubDeploymentStatus) error
+	CreateTag(config *models.Configuration, tag string, message string, sha string) (*models.GitTag, error)
+	CreateRelease(config *models.Configuration, tag string, name string, body string) (*models.GithubRelease, error)
+	ListTags(config *models.Configuration) ([]models.Tag, error)
+	ListCommits(config *models.Configuration, ref string) ([]models.Commit, error)
+	CompareCommits(config *models.Configuration, base string, head string) (*models.CompareResponse, error)
+	GetAuthenticatedUser() (*models.GithubUser, error)
 }
 
 type githubClient struct {
@@ -27,10 +61,15 @@ type githubClient struct {
 }
 
 func NewGithubClient() GithubClient {
+	return NewGithubClientWithToken("<<TOKEN>>")
+}
+
+//NewGithubClientWithToken initializes a Github client which performs the requests on behalf of the token owner.
+func NewGithubClientWithToken(token string) GithubClient {
 	hs := make(http.Header)
 	hs.Set("cache-control", "no-cache")
-	hs.Set("Authorization", "token <<TOKEN>>")
-	hs.Set("Accept", "application/vnd.github.luke-cage-preview+json")
+	hs.Set("Authorization", "token "+token)
+	hs.Set("Accept", "application/vnd.github.luke-cage-preview+json, application/vnd.github.antiope-preview+json, application/vnd.github.flash-preview+json")
 
 	return &githubClient{
 		Client: &client{
@@ -140,6 +179,28 @@ func (c *githubClient) CreateBranch(config *models.Configuration, branchConfig *
 	return nil
 }
 
+//DeleteBranch deletes the reference of a branch.
+//This perform a DELETE request to Github api
+func (c *githubClient) DeleteBranch(config *models.Configuration, name string) error {
+
+	if name == "" || config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	response := c.Client.Delete(fmt.Sprintf("/repos/%s/%s/git/refs/heads/%s", *config.RepositoryOwner, *config.RepositoryName, name))
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusNoContent {
+		return errors.New(fmt.Sprintf("error deleting a branch - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
 //Create a new reference on github. First we get the information needed to make the creation and then the creation itself.
 //This perform a GetBranchInformation and CreateBranch
 func (c *githubClient) CreateGithubRef(config *models.Configuration, branchConfig *models.Branch, workflowConfig *models.WorkflowConfig) error {
@@ -197,3 +258,643 @@ func (c *githubClient) SetDefaultBranch(config *models.Configuration, workflowCo
 
 	return nil
 }
+
+//CreateWebhook registers a repository webhook which reports the repository events back to this API.
+//This perform a POST request to Github api
+func (c *githubClient) CreateWebhook(config *models.Configuration) (*models.Webhook, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"name":   "web",
+		"active": true,
+		"events": configs.GetWebhookEvents(),
+		"config": map[string]interface{}{
+			"url":          configs.GetWebhookURL(),
+			"content_type": "json",
+			"secret":       configs.GetWebhookSecret(),
+		},
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/hooks", *config.RepositoryOwner, *config.RepositoryName), body)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return nil, errors.New(fmt.Sprintf("error creating webhook - status: %d", response.StatusCode()))
+	}
+
+	var hook models.Webhook
+	if err := json.Unmarshal(response.Bytes(), &hook); err != nil {
+		return nil, errors.New("error binding github webhook response")
+	}
+
+	return &hook, nil
+}
+
+//GetWebhook gets the repository webhook registered for the given configuration
+//This perform a GET request to Github api
+func (c *githubClient) GetWebhook(config *models.Configuration) (*models.Webhook, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || config.WebhookID == nil {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/hooks/%d", *config.RepositoryOwner, *config.RepositoryName, *config.WebhookID))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, errors.New(fmt.Sprintf("error getting webhook - status: %d", response.StatusCode()))
+	}
+
+	var hook models.Webhook
+	if err := json.Unmarshal(response.Bytes(), &hook); err != nil {
+		return nil, errors.New("error binding github webhook response")
+	}
+
+	return &hook, nil
+}
+
+//DeleteWebhook removes the repository webhook registered for the given configuration
+//This perform a DELETE request to Github api
+func (c *githubClient) DeleteWebhook(config *models.Configuration) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || config.WebhookID == nil {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	response := c.Client.Delete(fmt.Sprintf("/repos/%s/%s/hooks/%d", *config.RepositoryOwner, *config.RepositoryName, *config.WebhookID))
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusNoContent {
+		if response.StatusCode() == http.StatusNotFound {
+			return ErrWebhookNotFound
+		}
+		return errors.New(fmt.Sprintf("error deleting webhook - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//CreatePullRequest opens a pull request to merge the head branch into the base one.
+//This perform a POST request to Github api
+func (c *githubClient) CreatePullRequest(config *models.Configuration, head string, base string, title string, body string) (*models.PullRequest, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || head == "" || base == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	reqBody := map[string]interface{}{
+		"title": title,
+		"head":  head,
+		"base":  base,
+		"body":  body,
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/pulls", *config.RepositoryOwner, *config.RepositoryName), reqBody)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return nil, errors.New(fmt.Sprintf("error creating pull request - status: %d", response.StatusCode()))
+	}
+
+	var pr models.PullRequest
+	if err := json.Unmarshal(response.Bytes(), &pr); err != nil {
+		return nil, errors.New("error binding github pull request response")
+	}
+
+	return &pr, nil
+}
+
+//GetPullRequest gets a pull request of the repository.
+//This perform a GET request to Github api
+func (c *githubClient) GetPullRequest(config *models.Configuration, number int) (*models.PullRequest, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || number == 0 {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/pulls/%d", *config.RepositoryOwner, *config.RepositoryName, number))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			return nil, ErrPullRequestNotFound
+		}
+		return nil, errors.New(fmt.Sprintf("error getting pull request - status: %d", response.StatusCode()))
+	}
+
+	var pr models.PullRequest
+	if err := json.Unmarshal(response.Bytes(), &pr); err != nil {
+		return nil, errors.New("error binding github pull request response")
+	}
+
+	return &pr, nil
+}
+
+//FindPullRequest gets the open pull request which merges the head branch into the base one.
+//It returns nil if there is none.
+//This perform a GET request to Github api
+func (c *githubClient) FindPullRequest(config *models.Configuration, head string, base string) (*models.PullRequest, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || head == "" || base == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/pulls?state=open&head=%s&base=%s", *config.RepositoryOwner, *config.RepositoryName, url.QueryEscape(*config.RepositoryOwner+":"+head), url.QueryEscape(base)))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("error listing pull requests - status: %d", response.StatusCode()))
+	}
+
+	var prs []models.PullRequest
+	if err := json.Unmarshal(response.Bytes(), &prs); err != nil {
+		return nil, errors.New("error binding github pull requests response")
+	}
+
+	if len(prs) == 0 {
+		return nil, nil
+	}
+
+	return &prs[0], nil
+}
+
+//MergePullRequest merges a pull request into its base branch.
+//This perform a PUT request to Github api
+func (c *githubClient) MergePullRequest(config *models.Configuration, number int, commitTitle string) (*models.MergePullRequestResponse, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || number == 0 {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"commit_title": commitTitle,
+		"merge_method": "merge",
+	}
+
+	response := c.Client.Put(fmt.Sprintf("/repos/%s/%s/pulls/%d/merge", *config.RepositoryOwner, *config.RepositoryName, number), body)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("error merging pull request - status: %d", response.StatusCode()))
+	}
+
+	var merge models.MergePullRequestResponse
+	if err := json.Unmarshal(response.Bytes(), &merge); err != nil {
+		return nil, errors.New("error binding github merge response")
+	}
+
+	return &merge, nil
+}
+
+//GetCombinedStatus gets the combined status of every context reported for a ref (branch, tag or sha).
+//This perform a GET request to Github api
+func (c *githubClient) GetCombinedStatus(config *models.Configuration, ref string) (*models.CombinedStatus, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || ref == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/commits/%s/status", *config.RepositoryOwner, *config.RepositoryName, ref))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("error getting combined status - status: %d", response.StatusCode()))
+	}
+
+	var status models.CombinedStatus
+	if err := json.Unmarshal(response.Bytes(), &status); err != nil {
+		return nil, errors.New("error binding github combined status response")
+	}
+
+	return &status, nil
+}
+
+//CreateStatus reports the status of a context for a commit.
+//This perform a POST request to Github api
+func (c *githubClient) CreateStatus(config *models.Configuration, sha string, status *models.CommitStatus) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || sha == "" || status.State == "" {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/statuses/%s", *config.RepositoryOwner, *config.RepositoryName, sha), status)
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return errors.New(fmt.Sprintf("error creating commit status - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//ListCheckRuns lists the check runs of a commit with the given name.
+//This perform a GET request to Github api
+func (c *githubClient) ListCheckRuns(config *models.Configuration, sha string, name string) ([]models.CheckRun, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || sha == "" || name == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs?check_name=%s", *config.RepositoryOwner, *config.RepositoryName, sha, url.QueryEscape(name)))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("error listing check runs - status: %d", response.StatusCode()))
+	}
+
+	var list models.CheckRunList
+	if err := json.Unmarshal(response.Bytes(), &list); err != nil {
+		return nil, errors.New("error binding github check runs response")
+	}
+
+	return list.CheckRuns, nil
+}
+
+//CreateCheckRun creates a check run for a commit.
+//This perform a POST request to Github api
+func (c *githubClient) CreateCheckRun(config *models.Configuration, run *models.CheckRun) (*models.CheckRun, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || run.Name == "" || run.HeadSha == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/check-runs", *config.RepositoryOwner, *config.RepositoryName), run)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return nil, errors.New(fmt.Sprintf("error creating check run - status: %d", response.StatusCode()))
+	}
+
+	var created models.CheckRun
+	if err := json.Unmarshal(response.Bytes(), &created); err != nil {
+		return nil, errors.New("error binding github check run response")
+	}
+
+	return &created, nil
+}
+
+//UpdateCheckRun updates the status, conclusion and output of a check run.
+//This perform a PATCH request to Github api
+func (c *githubClient) UpdateCheckRun(config *models.Configuration, id int64, run *models.CheckRun) (*models.CheckRun, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || id == 0 {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	response := c.Client.Patch(fmt.Sprintf("/repos/%s/%s/check-runs/%d", *config.RepositoryOwner, *config.RepositoryName, id), run)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			return nil, errors.New("check run not found")
+		}
+		return nil, errors.New(fmt.Sprintf("error updating check run - status: %d", response.StatusCode()))
+	}
+
+	var updated models.CheckRun
+	if err := json.Unmarshal(response.Bytes(), &updated); err != nil {
+		return nil, errors.New("error binding github check run response")
+	}
+
+	return &updated, nil
+}
+
+//CreateDeployment creates a deployment of a ref into an environment.
+//The ref is not merged with the default branch, it is deployed as it is.
+//This perform a POST request to Github api
+func (c *githubClient) CreateDeployment(config *models.Configuration, ref string, environment string, description string) (*models.GithubDeployment, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || ref == "" || environment == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"ref":         ref,
+		"environment": environment,
+		"description": description,
+		"auto_merge":  false,
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/deployments", *config.RepositoryOwner, *config.RepositoryName), body)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusCreated {
+		if response.StatusCode() == http.StatusConflict {
+			return nil, errors.New("required status checks are not successful")
+		}
+		return nil, errors.New(fmt.Sprintf("error creating deployment - status: %d", response.StatusCode()))
+	}
+
+	var deployment models.GithubDeployment
+	if err := json.Unmarshal(response.Bytes(), &deployment); err != nil {
+		return nil, errors.New("error binding github deployment response")
+	}
+
+	return &deployment, nil
+}
+
+//CreateDeploymentStatus reports the progress of a deployment.
+//This perform a POST request to Github api
+func (c *githubClient) CreateDeploymentStatus(config *models.Configuration, id int64, status *models.GithubDeploymentStatus) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || id == 0 || status.State == "" {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/deployments/%d/statuses", *config.RepositoryOwner, *config.RepositoryName, id), status)
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusCreated {
+		if response.StatusCode() == http.StatusNotFound {
+			return errors.New("deployment not found")
+		}
+		return errors.New(fmt.Sprintf("error creating deployment status - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//CreateTag creates an annotated tag pointing to the given commit.
+//First we create the tag object and then the reference to it.
+//This perform two POST requests to Github api
+func (c *githubClient) CreateTag(config *models.Configuration, tag string, message string, sha string) (*models.GitTag, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || tag == "" || sha == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	tagBody := map[string]interface{}{
+		"tag":     tag,
+		"message": message,
+		"object":  sha,
+		"type":    "commit",
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/git/tags", *config.RepositoryOwner, *config.RepositoryName), tagBody)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return nil, errors.New(fmt.Sprintf("error creating tag - status: %d", response.StatusCode()))
+	}
+
+	var gitTag models.GitTag
+	if err := json.Unmarshal(response.Bytes(), &gitTag); err != nil {
+		return nil, errors.New("error binding github tag response")
+	}
+
+	refBody := map[string]interface{}{
+		"ref": fmt.Sprintf("refs/tags/%s", tag),
+		"sha": gitTag.Sha,
+	}
+
+	response = c.Client.Post(fmt.Sprintf("/repos/%s/%s/git/refs", *config.RepositoryOwner, *config.RepositoryName), refBody)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return nil, errors.New(fmt.Sprintf("error creating tag reference - status: %d", response.StatusCode()))
+	}
+
+	return &gitTag, nil
+}
+
+//CreateRelease publishes a Github release for an existing tag.
+//This perform a POST request to Github api
+func (c *githubClient) CreateRelease(config *models.Configuration, tag string, name string, body string) (*models.GithubRelease, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || tag == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	reqBody := map[string]interface{}{
+		"tag_name": tag,
+		"name":     name,
+		"body":     body,
+	}
+
+	response := c.Client.Post(fmt.Sprintf("/repos/%s/%s/releases", *config.RepositoryOwner, *config.RepositoryName), reqBody)
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return nil, errors.New(fmt.Sprintf("error creating release - status: %d", response.StatusCode()))
+	}
+
+	var release models.GithubRelease
+	if err := json.Unmarshal(response.Bytes(), &release); err != nil {
+		return nil, errors.New("error binding github release response")
+	}
+
+	return &release, nil
+}
+
+//githubPageSize is the number of elements requested per page of the Github lists, the maximum allowed by Github.
+const githubPageSize = 100
+
+//ListTags gets all the tags of a repository.
+//The tags are listed page by page until a page is not full.
+//This perform a GET request per page to Github api
+func (c *githubClient) ListTags(config *models.Configuration) ([]models.Tag, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	tags := make([]models.Tag, 0)
+	for page := 1; ; page++ {
+		response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/tags?per_page=%d&page=%d", *config.RepositoryOwner, *config.RepositoryName, githubPageSize, page))
+
+		if response.Err() != nil {
+			return nil, response.Err()
+		}
+
+		if response.StatusCode() != http.StatusOK {
+			return nil, errors.New(fmt.Sprintf("error listing tags - status: %d", response.StatusCode()))
+		}
+
+		var pageTags []models.Tag
+		if err := json.Unmarshal(response.Bytes(), &pageTags); err != nil {
+			return nil, errors.New("error binding github tags response")
+		}
+
+		tags = append(tags, pageTags...)
+
+		if len(pageTags) < githubPageSize {
+			return tags, nil
+		}
+	}
+}
+
+//ListCommits gets all the commits reachable from a ref (branch, tag or sha), the latest first.
+//The commits are listed page by page until a page is not full.
+//This perform a GET request per page to Github api
+func (c *githubClient) ListCommits(config *models.Configuration, ref string) ([]models.Commit, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || ref == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	commits := make([]models.Commit, 0)
+	for page := 1; ; page++ {
+		response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/commits?sha=%s&per_page=%d&page=%d", *config.RepositoryOwner, *config.RepositoryName, url.QueryEscape(ref), githubPageSize, page))
+
+		if response.Err() != nil {
+			return nil, response.Err()
+		}
+
+		if response.StatusCode() != http.StatusOK {
+			return nil, errors.New(fmt.Sprintf("error listing commits - status: %d", response.StatusCode()))
+		}
+
+		var pageCommits []models.Commit
+		if err := json.Unmarshal(response.Bytes(), &pageCommits); err != nil {
+			return nil, errors.New("error binding github commits response")
+		}
+
+		commits = append(commits, pageCommits...)
+
+		if len(pageCommits) < githubPageSize {
+			return commits, nil
+		}
+	}
+}
+
+//CompareCommits gets the commits reachable from head which are not reachable from base.
+//The commits of the comparison are listed page by page until all of them are fetched.
+//This perform a GET request per page to Github api
+func (c *githubClient) CompareCommits(config *models.Configuration, base string, head string) (*models.CompareResponse, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || base == "" || head == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	var compare *models.CompareResponse
+	for page := 1; ; page++ {
+		response := c.Client.Get(fmt.Sprintf("/repos/%s/%s/compare/%s...%s?per_page=%d&page=%d", *config.RepositoryOwner, *config.RepositoryName, url.PathEscape(base), url.PathEscape(head), githubPageSize, page))
+
+		if response.Err() != nil {
+			return nil, response.Err()
+		}
+
+		if response.StatusCode() != http.StatusOK {
+			if response.StatusCode() == http.StatusNotFound {
+				return nil, errors.New("ref not found")
+			}
+			return nil, errors.New(fmt.Sprintf("error comparing commits - status: %d", response.StatusCode()))
+		}
+
+		var pageCompare models.CompareResponse
+		if err := json.Unmarshal(response.Bytes(), &pageCompare); err != nil {
+			return nil, errors.New("error binding github compare response")
+		}
+
+		if compare == nil {
+			compare = &pageCompare
+		} else {
+			compare.Commits = append(compare.Commits, pageCompare.Commits...)
+		}
+
+		if len(pageCompare.Commits) < githubPageSize || len(compare.Commits) >= compare.TotalCommits {
+			return compare, nil
+		}
+	}
+}
+
+//GetAuthenticatedUser gets the Github user the client token belongs to
+//This perform a GET request to Github api
+func (c *githubClient) GetAuthenticatedUser() (*models.GithubUser, error) {
+
+	response := c.Client.Get("/user")
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusUnauthorized {
+			return nil, ErrInvalidToken
+		}
+		return nil, errors.New(fmt.Sprintf("error getting authenticated user - status: %d", response.StatusCode()))
+	}
+
+	var user models.GithubUser
+	if err := json.Unmarshal(response.Bytes(), &user); err != nil {
+		return nil, errors.New("error binding github user response")
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,114 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/mercadolibre/golang-restclient/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_githubClient_ListTags(t *testing.T) {
+	total := githubPageSize + 20
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/herbal828/ci_cd-api/tags" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		tags := make([]models.Tag, 0)
+		for i := (page - 1) * githubPageSize; i < page*githubPageSize && i < total; i++ {
+			tags = append(tags, models.Tag{Name: fmt.Sprintf("v0.%d.0", i)})
+		}
+		json.NewEncoder(w).Encode(tags)
+	}))
+	defer server.Close()
+
+	c := newTestGithubClient(server)
+
+	tags, err := c.ListTags(testGithubConfiguration())
+
+	assert.NoError(t, err)
+	assert.Len(t, tags, total)
+	assert.Equal(t, "v0.0.0", tags[0].Name)
+	assert.Equal(t, fmt.Sprintf("v0.%d.0", total-1), tags[total-1].Name)
+}
+
+func Test_githubClient_ListCommits(t *testing.T) {
+	total := githubPageSize + 5
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/herbal828/ci_cd-api/commits" || r.URL.Query().Get("sha") != "feature/a&b" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		commits := make([]models.Commit, 0)
+		for i := (page - 1) * githubPageSize; i < page*githubPageSize && i < total; i++ {
+			commits = append(commits, models.Commit{Sha: fmt.Sprintf("sha%d", i)})
+		}
+		json.NewEncoder(w).Encode(commits)
+	}))
+	defer server.Close()
+
+	c := newTestGithubClient(server)
+
+	commits, err := c.ListCommits(testGithubConfiguration(), "feature/a&b")
+
+	assert.NoError(t, err)
+	assert.Len(t, commits, total)
+	assert.Equal(t, fmt.Sprintf("sha%d", total-1), commits[total-1].Sha)
+}
+
+func Test_githubClient_CompareCommits(t *testing.T) {
+	total := githubPageSize + 5
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/herbal828/ci_cd-api/compare/v1.0.0...feature/a" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		compare := models.CompareResponse{Status: "ahead", AheadBy: total, TotalCommits: total}
+		for i := (page - 1) * githubPageSize; i < page*githubPageSize && i < total; i++ {
+			compare.Commits = append(compare.Commits, models.Commit{Sha: fmt.Sprintf("sha%d", i)})
+		}
+		json.NewEncoder(w).Encode(compare)
+	}))
+	defer server.Close()
+
+	c := newTestGithubClient(server)
+
+	compare, err := c.CompareCommits(testGithubConfiguration(), "v1.0.0", "feature/a")
+
+	assert.NoError(t, err)
+	assert.Equal(t, total, compare.TotalCommits)
+	assert.Len(t, compare.Commits, total)
+	assert.Equal(t, fmt.Sprintf("sha%d", total-1), compare.Commits[total-1].Sha)
+}
+
+func newTestGithubClient(server *httptest.Server) *githubClient {
+	return &githubClient{
+		Client: &client{
+			RestClient: &rest.RequestBuilder{
+				BaseURL:      server.URL,
+				ContentType:  rest.JSON,
+				DisableCache: true,
+			},
+		},
+	}
+}
+
+func testGithubConfiguration() *models.Configuration {
+	return &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+}
@@ -0,0 +1,339 @@
+package clients
+
+// Jenkins client, provisions the continuous integration job of every configured repository
+// as a multibranch pipeline built from the configs template
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/mercadolibre/golang-restclient/rest"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//jenkinsQueueItemPattern matches the queue item of the Location header of a scheduled build.
+var jenkinsQueueItemPattern = regexp.MustCompile(`/queue/item/(\d+)/?$`)
+
+type jenkinsClient struct {
+	Client Client
+}
+
+func NewJenkinsClient() CIBuilderClient {
+	hs := make(http.Header)
+	hs.Set("cache-control", "no-cache")
+	user, token := configs.GetJenkinsCredentials()
+	hs.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+token)))
+	hs.Set("Content-Type", "application/xml")
+
+	return &jenkinsClient{
+		Client: &client{
+			RestClient: &rest.RequestBuilder{
+				BaseURL:        configs.GetJenkinsBaseURL(),
+				Timeout:        5 * time.Second,
+				Headers:        hs,
+				ContentType:    rest.BYTES,
+				DisableCache:   true,
+				DisableTimeout: false,
+			},
+		},
+	}
+}
+
+//CreateJob creates the multibranch pipeline of the repository.
+//If the job already exists, its configuration is replaced by the template one.
+//This perform GET requests for the job and a CSRF crumb and a POST request to Jenkins api
+func (c *jenkinsClient) CreateJob(config *models.Configuration) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	jobName := url.PathEscape(configs.GetJenkinsJobName(config))
+	jobConfig := configs.GetJenkinsJobConfig(config)
+
+	response := c.Client.Get(fmt.Sprintf("/job/%s/api/json", jobName))
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	switch response.StatusCode() {
+	case http.StatusOK:
+		response = c.post(fmt.Sprintf("/job/%s/config.xml", jobName), jobConfig)
+	case http.StatusNotFound:
+		response = c.post(fmt.Sprintf("/createItem?name=%s", url.QueryEscape(configs.GetJenkinsJobName(config))), jobConfig)
+	default:
+		return errors.New(fmt.Sprintf("error getting jenkins job - status: %d", response.StatusCode()))
+	}
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return errors.New(fmt.Sprintf("error creating jenkins job - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//DeleteJob deletes the multibranch pipeline of the repository along with all its builds.
+//This perform a GET request for a CSRF crumb and a POST request to Jenkins api
+func (c *jenkinsClient) DeleteJob(config *models.Configuration) error {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil {
+		err := errors.New("invalid body params")
+		return err
+	}
+
+	response := c.post(fmt.Sprintf("/job/%s/doDelete", url.PathEscape(configs.GetJenkinsJobName(config))), []byte{})
+
+	if response.Err() != nil {
+		return response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusFound {
+		if response.StatusCode() == http.StatusNotFound {
+			return ErrJobNotFound
+		}
+		return errors.New(fmt.Sprintf("error deleting jenkins job - status: %d", response.StatusCode()))
+	}
+
+	return nil
+}
+
+//jenkinsShaParameter is the build parameter the commit to build is passed with.
+//The Jenkinsfile of the repositories must declare it to check out the exact commit.
+const jenkinsShaParameter = "SHA"
+
+//jenkinsQueuedBuildPrefix prefixes the queue item of the build IDs of the builds still waiting in the Jenkins queue.
+const jenkinsQueuedBuildPrefix = "queue-"
+
+//TriggerBuild schedules a build of the commit in the branch job inside the repository multibranch pipeline.
+//Jenkins does not know the number of the build until it leaves the queue, so the build ID is composed by
+//the branch name and its queue item. It is replaced by the build number once the build starts.
+//This perform GET requests for the job and a CSRF crumb and a POST request to Jenkins api
+func (c *jenkinsClient) TriggerBuild(config *models.Configuration, branch string, sha string) (*models.BuildStatus, error) {
+
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || branch == "" || sha == "" {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	branchJob := jenkinsBranchJobPath(config, branch)
+
+	response := c.Client.Get(fmt.Sprintf("%s/api/json", branchJob))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			return nil, ErrJobNotFound
+		}
+		return nil, errors.New(fmt.Sprintf("error getting jenkins job - status: %d", response.StatusCode()))
+	}
+
+	response = c.post(fmt.Sprintf("%s/buildWithParameters?%s=%s", branchJob, jenkinsShaParameter, url.QueryEscape(sha)), []byte{})
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusCreated {
+		return nil, errors.New(fmt.Sprintf("error triggering jenkins build - status: %d", response.StatusCode()))
+	}
+
+	item := jenkinsQueueItemPattern.FindStringSubmatch(response.Header().Get("Location"))
+	if item == nil {
+		return nil, errors.New("error getting jenkins queue item")
+	}
+
+	return &models.BuildStatus{
+		ID:    fmt.Sprintf("%s:%s%s", branch, jenkinsQueuedBuildPrefix, item[1]),
+		State: models.BuildStatePending,
+	}, nil
+}
+
+//GetBuildStatus gets the status of a build of a branch job inside the repository multibranch pipeline.
+//The builds still waiting in the queue are looked up through their queue item.
+//This perform a GET request to Jenkins api
+func (c *jenkinsClient) GetBuildStatus(config *models.Configuration, buildID string) (*models.BuildStatus, error) {
+
+	sep := strings.LastIndex(buildID, ":")
+	if config.RepositoryOwner == nil || config.RepositoryName == nil || sep <= 0 {
+		err := errors.New("invalid body params")
+		return nil, err
+	}
+
+	branch := buildID[:sep]
+
+	if strings.HasPrefix(buildID[sep+1:], jenkinsQueuedBuildPrefix) {
+		return c.getQueuedBuildStatus(config, branch, buildID)
+	}
+
+	number, err := strconv.Atoi(buildID[sep+1:])
+	if err != nil {
+		return nil, errors.New("invalid body params")
+	}
+
+	response := c.Client.Get(fmt.Sprintf("%s/%d/api/json", jenkinsBranchJobPath(config, branch), number))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("error getting jenkins build - status: %d", response.StatusCode()))
+	}
+
+	var build struct {
+		Building  bool   `json:"building"`
+		Result    string `json:"result"`
+		URL       string `json:"url"`
+		Timestamp int64  `json:"timestamp"`
+		Duration  int64  `json:"duration"`
+	}
+	if err := json.Unmarshal(response.Bytes(), &build); err != nil {
+		return nil, errors.New("error binding jenkins build response")
+	}
+
+	status := models.BuildStatus{
+		ID:     buildID,
+		LogURL: build.URL + "console",
+	}
+
+	if build.Timestamp > 0 {
+		startedAt := time.Unix(0, build.Timestamp*int64(time.Millisecond))
+		status.StartedAt = &startedAt
+	}
+
+	switch {
+	case build.Building:
+		status.State = models.BuildStateRunning
+	case build.Result == "SUCCESS":
+		status.State = models.BuildStateSuccess
+	case build.Result == "FAILURE" || build.Result == "UNSTABLE":
+		status.State = models.BuildStateFailure
+	default:
+		status.State = models.BuildStateError
+	}
+
+	if status.IsFinished() && status.StartedAt != nil {
+		finishedAt := status.StartedAt.Add(time.Duration(build.Duration) * time.Millisecond)
+		status.FinishedAt = &finishedAt
+	}
+
+	return &status, nil
+}
+
+//getQueuedBuildStatus gets the status of a build through its queue item.
+//Once the build leaves the queue, the status of the build is returned with its number as build ID.
+//This perform a GET request to Jenkins api
+func (c *jenkinsClient) getQueuedBuildStatus(config *models.Configuration, branch string, buildID string) (*models.BuildStatus, error) {
+
+	item, err := strconv.Atoi(strings.TrimPrefix(buildID[len(branch)+1:], jenkinsQueuedBuildPrefix))
+	if err != nil {
+		return nil, errors.New("invalid body params")
+	}
+
+	response := c.Client.Get(fmt.Sprintf("/queue/item/%d/api/json", item))
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			//Jenkins forgets the queue items a while after they leave the queue
+			return &models.BuildStatus{ID: buildID, State: models.BuildStateError}, nil
+		}
+		return nil, errors.New(fmt.Sprintf("error getting jenkins queue item - status: %d", response.StatusCode()))
+	}
+
+	var queued struct {
+		Cancelled  bool `json:"cancelled"`
+		Executable *struct {
+			Number int `json:"number"`
+		} `json:"executable"`
+	}
+	if err := json.Unmarshal(response.Bytes(), &queued); err != nil {
+		return nil, errors.New("error binding jenkins queue item response")
+	}
+
+	switch {
+	case queued.Cancelled:
+		return &models.BuildStatus{ID: buildID, State: models.BuildStateError}, nil
+	case queued.Executable == nil:
+		return &models.BuildStatus{ID: buildID, State: models.BuildStatePending}, nil
+	default:
+		return c.GetBuildStatus(config, fmt.Sprintf("%s:%d", branch, queued.Executable.Number))
+	}
+}
+
+//jenkinsCrumb is the token Jenkins requires on the POST requests when its CSRF protection is enabled.
+type jenkinsCrumb struct {
+	Field string `json:"crumbRequestField"`
+	Value string `json:"crumb"`
+}
+
+//getCrumb issues a CSRF crumb, it returns nil when the CSRF protection of Jenkins is disabled.
+//This perform a GET request to Jenkins api
+func (c *jenkinsClient) getCrumb() (*jenkinsCrumb, error) {
+	response := c.Client.Get("/crumbIssuer/api/json")
+
+	if response.Err() != nil {
+		return nil, response.Err()
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		if response.StatusCode() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, errors.New(fmt.Sprintf("error getting jenkins crumb - status: %d", response.StatusCode()))
+	}
+
+	var crumb jenkinsCrumb
+	if err := json.Unmarshal(response.Bytes(), &crumb); err != nil || crumb.Field == "" {
+		return nil, errors.New("error binding jenkins crumb response")
+	}
+
+	return &crumb, nil
+}
+
+//post performs a POST request to Jenkins api with a CSRF crumb.
+//Jenkins accepts the crumb either as a header or as a request parameter, it is sent as a parameter
+//since the headers of the client are shared by all the requests.
+func (c *jenkinsClient) post(path string, body []byte) Response {
+	crumb, err := c.getCrumb()
+
+	if err != nil {
+		return &rawResponse{err: err}
+	}
+
+	if crumb != nil {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + url.QueryEscape(crumb.Field) + "=" + url.QueryEscape(crumb.Value)
+	}
+
+	return c.Client.Post(path, body)
+}
+
+//jenkinsBranchJobPath returns the path of the branch job inside the repository multibranch pipeline.
+//Jenkins encodes the branch name to build the job name, so it must be escaped twice in the URL.
+func jenkinsBranchJobPath(config *models.Configuration, branch string) string {
+	return fmt.Sprintf("/job/%s/job/%s", url.PathEscape(configs.GetJenkinsJobName(config)), url.PathEscape(url.PathEscape(branch)))
+}
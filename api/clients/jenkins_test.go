@@ -0,0 +1,233 @@
+package clients
+
+import (
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mercadolibre/golang-restclient/rest"
+)
+
+//fakeJenkinsCrumb is the CSRF crumb issued by the fake Jenkins server.
+const fakeJenkinsCrumb = "c0ffee"
+
+//fakeJenkins is a minimal Jenkins server which keeps the jobs config.xml in memory.
+//It requires a CSRF crumb on every POST request.
+type fakeJenkins struct {
+	jobs map[string]string
+}
+
+func (f *fakeJenkins) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	if r.Method == http.MethodPost && r.URL.Query().Get("Jenkins-Crumb") != fakeJenkinsCrumb {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/crumbIssuer/api/json":
+		w.Write([]byte(`{"crumbRequestField":"Jenkins-Crumb","crumb":"` + fakeJenkinsCrumb + `"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/createItem":
+		if r.Header.Get("Content-Type") != "application/xml" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		name := r.URL.Query().Get("name")
+		if _, ok := f.jobs[name]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.jobs[name] = string(body)
+	case strings.HasPrefix(r.URL.Path, "/job/"):
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/job/"), "/", 2)
+		if _, ok := f.jobs[parts[0]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch parts[1] {
+		case "api/json":
+			w.Write([]byte(`{"name":"` + parts[0] + `"}`))
+		case "config.xml":
+			f.jobs[parts[0]] = string(body)
+		case "doDelete":
+			delete(f.jobs, parts[0])
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestJenkinsClient(server *httptest.Server) *jenkinsClient {
+	hs := make(http.Header)
+	hs.Set("Content-Type", "application/xml")
+
+	return &jenkinsClient{
+		Client: &client{
+			RestClient: &rest.RequestBuilder{
+				BaseURL:      server.URL,
+				Headers:      hs,
+				ContentType:  rest.BYTES,
+				DisableCache: true,
+			},
+		},
+	}
+}
+
+func Test_jenkinsClient_CreateJob(t *testing.T) {
+	fake := &fakeJenkins{jobs: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c := newTestJenkinsClient(server)
+	config := &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+
+	if err := c.CreateJob(config); err != nil {
+		t.Fatalf("jenkinsClient.CreateJob() error = %v", err)
+	}
+
+	job, ok := fake.jobs["herbal828-ci_cd-api"]
+	if !ok {
+		t.Fatalf("jenkinsClient.CreateJob() did not create the job, jobs = %v", fake.jobs)
+	}
+
+	if !strings.Contains(job, "<repoOwner>herbal828</repoOwner>") || !strings.Contains(job, "<repository>ci_cd-api</repository>") {
+		t.Errorf("jenkinsClient.CreateJob() job config = %s", job)
+	}
+
+	//Creating the job again updates its configuration
+	fake.jobs["herbal828-ci_cd-api"] = "outdated"
+	if err := c.CreateJob(config); err != nil {
+		t.Fatalf("jenkinsClient.CreateJob() on an existing job error = %v", err)
+	}
+
+	if fake.jobs["herbal828-ci_cd-api"] == "outdated" {
+		t.Errorf("jenkinsClient.CreateJob() did not update the existing job")
+	}
+
+	if err := c.CreateJob(&models.Configuration{}); err == nil {
+		t.Errorf("jenkinsClient.CreateJob() without repository should fail")
+	}
+}
+
+func Test_jenkinsClient_CreateJob_JenkinsError(t *testing.T) {
+	posted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = true
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := newTestJenkinsClient(server)
+	config := &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+
+	if err := c.CreateJob(config); err == nil {
+		t.Errorf("jenkinsClient.CreateJob() on a Jenkins error should fail")
+	}
+
+	if posted {
+		t.Errorf("jenkinsClient.CreateJob() on a Jenkins error should not create the job")
+	}
+}
+
+func Test_jenkinsClient_DeleteJob(t *testing.T) {
+	fake := &fakeJenkins{jobs: map[string]string{"herbal828-ci_cd-api": "<project/>"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c := newTestJenkinsClient(server)
+	config := &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+
+	if err := c.DeleteJob(config); err != nil {
+		t.Fatalf("jenkinsClient.DeleteJob() error = %v", err)
+	}
+
+	if _, ok := fake.jobs["herbal828-ci_cd-api"]; ok {
+		t.Errorf("jenkinsClient.DeleteJob() did not delete the job")
+	}
+
+	if err := c.DeleteJob(config); err == nil || err != ErrJobNotFound {
+		t.Errorf("jenkinsClient.DeleteJob() on a missing job error = %v, want job not found", err)
+	}
+}
+
+func Test_jenkinsClient_TriggerBuild(t *testing.T) {
+	var builtSha string
+	started := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/crumbIssuer/api/json":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/job/herbal828-ci_cd-api/job/feature%2Fbuilds/api/json":
+			w.Write([]byte(`{"name":"feature%2Fbuilds"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/job/herbal828-ci_cd-api/job/feature%2Fbuilds/buildWithParameters":
+			builtSha = r.URL.Query().Get("SHA")
+			w.Header().Set("Location", "http://jenkins.test/queue/item/42/")
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/queue/item/42/api/json":
+			if !started {
+				w.Write([]byte(`{"id":42,"why":"Waiting for next available executor"}`))
+				return
+			}
+			w.Write([]byte(`{"id":42,"executable":{"number":7,"url":"http://jenkins.test/job/herbal828-ci_cd-api/job/feature%252Fbuilds/7/"}}`))
+		case r.URL.Path == "/job/herbal828-ci_cd-api/job/feature%2Fbuilds/7/api/json":
+			w.Write([]byte(`{"building":true,"url":"http://jenkins.test/job/herbal828-ci_cd-api/job/feature%252Fbuilds/7/","timestamp":1570000000000}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestJenkinsClient(server)
+	config := &models.Configuration{
+		RepositoryName:  utils.Stringify("ci_cd-api"),
+		RepositoryOwner: utils.Stringify("herbal828"),
+	}
+
+	status, err := c.TriggerBuild(config, "feature/builds", "abc123")
+	if err != nil {
+		t.Fatalf("jenkinsClient.TriggerBuild() error = %v", err)
+	}
+
+	if builtSha != "abc123" {
+		t.Errorf("jenkinsClient.TriggerBuild() built sha = %v, want abc123", builtSha)
+	}
+
+	if status.ID != "feature/builds:queue-42" || status.State != models.BuildStatePending {
+		t.Errorf("jenkinsClient.TriggerBuild() = %+v", status)
+	}
+
+	status, err = c.GetBuildStatus(config, status.ID)
+	if err != nil {
+		t.Fatalf("jenkinsClient.GetBuildStatus() of a queued build error = %v", err)
+	}
+
+	if status.ID != "feature/builds:queue-42" || status.State != models.BuildStatePending {
+		t.Errorf("jenkinsClient.GetBuildStatus() of a queued build = %+v", status)
+	}
+
+	started = true
+	status, err = c.GetBuildStatus(config, status.ID)
+	if err != nil {
+		t.Fatalf("jenkinsClient.GetBuildStatus() of a started build error = %v", err)
+	}
+
+	if status.ID != "feature/builds:7" || status.State != models.BuildStateRunning {
+		t.Errorf("jenkinsClient.GetBuildStatus() of a started build = %+v", status)
+	}
+}
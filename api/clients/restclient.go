@@ -1,6 +1,11 @@
 package clients
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 	"time"
 
 	"github.com/mercadolibre/golang-restclient/rest"
@@ -9,6 +14,7 @@ import (
 type Client interface {
 	Post(string, interface{}) Response
 	Put(string, interface{}) Response
+	Patch(string, interface{}) Response
 	Get(string) Response
 	Delete(string) Response
 }
@@ -32,6 +38,51 @@ func (c *client) Put(url string, body interface{}) Response {
 	return newResponse(r)
 }
 
+//Patch performs the request with the net/http client because the rest client
+//drops the body of the PATCH requests.
+func (c *client) Patch(url string, body interface{}) Response {
+	var b []byte
+	switch c.RestClient.ContentType {
+	case rest.BYTES:
+		var ok bool
+		if b, ok = body.([]byte); !ok {
+			return &rawResponse{err: fmt.Errorf("bytes: body is %T(%v) not a byte slice", body, body)}
+		}
+	default:
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return &rawResponse{err: err}
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, c.RestClient.BaseURL+url, bytes.NewReader(b))
+	if err != nil {
+		return &rawResponse{err: err}
+	}
+
+	for k, v := range c.RestClient.Headers {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	hc := &http.Client{Timeout: c.RestClient.Timeout}
+	res, err := hc.Do(req)
+	if err != nil {
+		return &rawResponse{err: err}
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	return &rawResponse{
+		body:   resBody,
+		err:    err,
+		status: res.StatusCode,
+		header: res.Header,
+	}
+}
+
 func (c *client) Delete(url string) Response {
 	r := c.RestClient.Delete(url)
 	return newResponse(r)
@@ -47,6 +98,7 @@ type Response interface {
 	Bytes() []byte
 	Err() error
 	StatusCode() int
+	Header() http.Header
 }
 
 type response struct {
@@ -70,3 +122,37 @@ func (r *response) Err() error {
 func (r *response) StatusCode() int {
 	return r.Response.StatusCode
 }
+
+func (r *response) Header() http.Header {
+	if r.Response.Response == nil {
+		return make(http.Header)
+	}
+	return r.Response.Header
+}
+
+//rawResponse is the Response of the requests which are not performed by the rest client.
+type rawResponse struct {
+	body   []byte
+	err    error
+	status int
+	header http.Header
+}
+
+func (r *rawResponse) Bytes() []byte {
+	return r.body
+}
+
+func (r *rawResponse) Err() error {
+	return r.err
+}
+
+func (r *rawResponse) StatusCode() int {
+	return r.status
+}
+
+func (r *rawResponse) Header() http.Header {
+	if r.header == nil {
+		return make(http.Header)
+	}
+	return r.header
+}
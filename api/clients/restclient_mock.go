@@ -6,6 +6,7 @@ package clients
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	http "net/http"
 	reflect "reflect"
 )
 
@@ -46,6 +47,20 @@ func (mr *MockClientMockRecorder) Post(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockClient)(nil).Post), arg0, arg1)
 }
 
+// Patch mocks base method
+func (m *MockClient) Patch(arg0 string, arg1 interface{}) Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0, arg1)
+	ret0, _ := ret[0].(Response)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch
+func (mr *MockClientMockRecorder) Patch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockClient)(nil).Patch), arg0, arg1)
+}
+
 // Put mocks base method
 func (m *MockClient) Put(arg0 string, arg1 interface{}) Response {
 	m.ctrl.T.Helper()
@@ -152,3 +167,17 @@ func (mr *MockResponseMockRecorder) StatusCode() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusCode", reflect.TypeOf((*MockResponse)(nil).StatusCode))
 }
+
+// Header mocks base method
+func (m *MockResponse) Header() http.Header {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(http.Header)
+	return ret0
+}
+
+// Header indicates an expected call of Header
+func (mr *MockResponseMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockResponse)(nil).Header))
+}
@@ -3,6 +3,9 @@ package clients
 import (
 	"errors"
 	"github.com/herbal828/ci_cd-api/api/utils"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -143,6 +146,94 @@ func Test_client_Put(t *testing.T) {
 	}
 }
 
+func Test_client_Patch(t *testing.T) {
+	type fields struct {
+		Client     Client
+		RestClient *rest.RequestBuilder
+	}
+	type args struct {
+		url  string
+		body interface{}
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   Response
+		body   interface{}
+	}{
+		{
+			name: "test just running the Patch func",
+			args: args{
+				url: "url_test",
+			},
+			fields: fields{
+				RestClient: &rest.RequestBuilder{
+					BaseURL: "http://testbaseurl.com",
+				},
+			},
+			want: newResponse(&rest.Response{
+				Err: errors.New("algo salio mal"),
+			}),
+			body: map[string]interface{}{
+				"repository_name": "fury_repo-name",
+				"type":            "gitflow",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &client{
+				RestClient: tt.fields.RestClient,
+			}
+			if got := c.Patch(tt.args.url, tt.args.body); reflect.DeepEqual(got, tt.want) {
+				t.Errorf("client.Patch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_Patch_SendsBody(t *testing.T) {
+	var gotMethod, gotBody, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotContentType = r.Header.Get("Content-Type")
+		b, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	c := &client{
+		RestClient: &rest.RequestBuilder{
+			BaseURL: server.URL,
+			Headers: http.Header{"Authorization": []string{"token abc"}},
+		},
+	}
+
+	got := c.Patch("/resource", map[string]string{"type": "gitflow"})
+
+	if got.Err() != nil {
+		t.Fatalf("client.Patch() error = %v", got.Err())
+	}
+	if got.StatusCode() != http.StatusOK {
+		t.Errorf("client.Patch() status = %v, want %v", got.StatusCode(), http.StatusOK)
+	}
+	if string(got.Bytes()) != `{"ok":true}` {
+		t.Errorf("client.Patch() body = %s", got.Bytes())
+	}
+	if gotMethod != http.MethodPatch {
+		t.Errorf("client.Patch() method = %v, want %v", gotMethod, http.MethodPatch)
+	}
+	if gotBody != `{"type":"gitflow"}` {
+		t.Errorf("client.Patch() sent body = %v, want %v", gotBody, `{"type":"gitflow"}`)
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("client.Patch() content type = %v, want application/json", gotContentType)
+	}
+}
+
 func Test_client_Delete(t *testing.T) {
 	type fields struct {
 		RestClient *rest.RequestBuilder
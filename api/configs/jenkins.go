@@ -0,0 +1,60 @@
+package configs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/models"
+)
+
+//jenkinsMultibranchTemplate is the config.xml of a multibranch pipeline which discovers the branches
+//and pull requests of a Github repository and builds them with its Jenkinsfile.
+const jenkinsMultibranchTemplate = `<?xml version='1.1' encoding='UTF-8'?>
+<org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject plugin="workflow-multibranch">
+  <description>Continuous integration of %[1]s/%[2]s, managed by ci_cd-api</description>
+  <sources class="jenkins.branch.MultiBranchProject$BranchSourceList" plugin="branch-api">
+    <data>
+      <jenkins.branch.BranchSource>
+        <source class="org.jenkinsci.plugins.github_branch_source.GitHubSCMSource" plugin="github-branch-source">
+          <id>%[3]s</id>
+          <credentialsId>github-token</credentialsId>
+          <repoOwner>%[1]s</repoOwner>
+          <repository>%[2]s</repository>
+          <traits>
+            <org.jenkinsci.plugins.github__branch__source.BranchDiscoveryTrait>
+              <strategyId>1</strategyId>
+            </org.jenkinsci.plugins.github__branch__source.BranchDiscoveryTrait>
+            <org.jenkinsci.plugins.github__branch__source.OriginPullRequestDiscoveryTrait>
+              <strategyId>1</strategyId>
+            </org.jenkinsci.plugins.github__branch__source.OriginPullRequestDiscoveryTrait>
+          </traits>
+        </source>
+      </jenkins.branch.BranchSource>
+    </data>
+  </sources>
+  <factory class="org.jenkinsci.plugins.workflow.multibranch.WorkflowBranchProjectFactory">
+    <scriptPath>Jenkinsfile</scriptPath>
+  </factory>
+</org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject>
+`
+
+//GetJenkinsJobName returns the name of the Jenkins job of a repository.
+func GetJenkinsJobName(config *models.Configuration) string {
+	return fmt.Sprintf("%s-%s", *config.RepositoryOwner, *config.RepositoryName)
+}
+
+//GetJenkinsJobConfig renders the multibranch pipeline config.xml for a repository.
+func GetJenkinsJobConfig(config *models.Configuration) []byte {
+	return []byte(fmt.Sprintf(
+		jenkinsMultibranchTemplate,
+		escapeXML(*config.RepositoryOwner),
+		escapeXML(*config.RepositoryName),
+		escapeXML(GetJenkinsJobName(config)),
+	))
+}
+
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
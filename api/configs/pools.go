@@ -1,6 +1,9 @@
 package configs
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 const (
 	githubProductionBaseURL  = "https://api.github.com"
@@ -23,4 +26,110 @@ func GetGithubBaseURL() string {
 	default:
 		return githubProductionBaseURL
 	}
-}
\ No newline at end of file
+}
+
+const (
+	webhookProductionBaseURL = "https://ci-cd-api.herbal828.com"
+	webhookTestBaseURL       = "http://test.ci-cd-api.melifrontends.com"
+	webhookLocalBaseURL      = "http://localhost:8080"
+)
+
+//GetWebhookURL returns the URL where Github must deliver the repository events.
+func GetWebhookURL() string {
+	switch scope := os.Getenv("SCOPE"); scope {
+	case "production":
+		return webhookProductionBaseURL + "/webhooks/github"
+	case "test":
+		return webhookTestBaseURL + "/webhooks/github"
+	default:
+		return webhookLocalBaseURL + "/webhooks/github"
+	}
+}
+
+//GetWebhookSecret returns the secret shared with Github to sign the repository events.
+//It is set through the GITHUB_WEBHOOK_SECRET environment variable.
+func GetWebhookSecret() string {
+	return os.Getenv("GITHUB_WEBHOOK_SECRET")
+}
+
+//GetCodecovWebhookSecret returns the secret Codecov signs its webhook notifications with.
+//It is set through the CODECOV_WEBHOOK_SECRET environment variable.
+func GetCodecovWebhookSecret() string {
+	return os.Getenv("CODECOV_WEBHOOK_SECRET")
+}
+
+//GetDeploymentTargetSecret returns the secret of a deployment target from its reference.
+//The secrets are never stored with the configurations, they are set through the
+//DEPLOYMENT_TARGET_SECRET_<REF> environment variables, e.g. DEPLOYMENT_TARGET_SECRET_K8S_PRODUCTION for k8s-production.
+func GetDeploymentTargetSecret(ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(ref))
+
+	return os.Getenv("DEPLOYMENT_TARGET_SECRET_" + name)
+}
+
+//GetWebhookEvents returns the list of Github events a repository webhook is subscribed to.
+func GetWebhookEvents() []string {
+	return []string{"push", "create", "pull_request", "status", "deployment_status"}
+}
+
+const (
+	jenkinsProductionBaseURL = "https://jenkins.herbal828.com"
+	jenkinsTestBaseURL       = "http://test.jenkins.melifrontends.com"
+	jenkinsLocalBaseURL      = "http://localhost:8081"
+)
+
+//GetJenkinsBaseURL returns the URL of the Jenkins server which runs the continuous integration jobs.
+func GetJenkinsBaseURL() string {
+	switch scope := os.Getenv("SCOPE"); scope {
+	case "production":
+		return jenkinsProductionBaseURL
+	case "test":
+		return jenkinsTestBaseURL
+	default:
+		return jenkinsLocalBaseURL
+	}
+}
+
+//GetJenkinsCredentials returns the user and the API token the Jenkins server is authenticated with.
+//They are set through the JENKINS_USER and JENKINS_API_TOKEN environment variables.
+func GetJenkinsCredentials() (string, string) {
+	return os.Getenv("JENKINS_USER"), os.Getenv("JENKINS_API_TOKEN")
+}
+
+const (
+	ciProxyProductionBaseURL = "http://rp-ci-proxy.melifrontends.com"
+	ciProxyTestBaseURL       = "http://test.rp-ci-proxy.melifrontends.com"
+	ciProxyLocalBaseURL      = "http://localhost:8082"
+)
+
+//GetCIProxyBaseURL returns the URL of the CI proxy which fronts the build system.
+func GetCIProxyBaseURL() string {
+	switch scope := os.Getenv("SCOPE"); scope {
+	case "production":
+		return ciProxyProductionBaseURL
+	case "test":
+		return ciProxyTestBaseURL
+	default:
+		return ciProxyLocalBaseURL
+	}
+}
+
+//GetCIBuilder returns the continuous integration backend used to build the repositories.
+//It could be 'jenkins' (default) or 'ci-proxy' to build through the CI proxy.
+func GetCIBuilder() string {
+	switch builder := os.Getenv("CI_BUILDER"); builder {
+	case "ci-proxy":
+		return builder
+	default:
+		return "jenkins"
+	}
+}
@@ -60,6 +60,53 @@ func GetGitflowConfig(configuration *models.Configuration) *models.WorkflowConfi
 		StartWith:    false,
 	}
 
+	//Supporting Branches
+	//They are not protected, but their names must follow the gitflow conventions
+
+	featureBranchConfig := models.Branch{
+		Stable:    false,
+		Name:      "feature",
+		StartWith: true,
+		Pattern:   "feature/*",
+	}
+
+	fixBranchConfig := models.Branch{
+		Stable:    false,
+		Name:      "fix",
+		StartWith: true,
+		Pattern:   "fix/*",
+	}
+
+	enhancementBranchConfig := models.Branch{
+		Stable:    false,
+		Name:      "enhancement",
+		StartWith: true,
+		Pattern:   "enhancement/*",
+	}
+
+	bugfixBranchConfig := models.Branch{
+		Stable:    false,
+		Name:      "bugfix",
+		StartWith: true,
+		Pattern:   "bugfix/*",
+	}
+
+	releaseBranchConfig := models.Branch{
+		Stable:     false,
+		Name:       "release",
+		Releasable: false,
+		StartWith:  true,
+		Pattern:    "release/x.y.z",
+	}
+
+	hotfixBranchConfig := models.Branch{
+		Stable:     false,
+		Name:       "hotfix",
+		Releasable: false,
+		StartWith:  true,
+		Pattern:    "hotfix/x.y.z",
+	}
+
 	//Build the gitflow configuration
 
 	gfConfig := models.WorkflowConfig{
@@ -69,6 +116,12 @@ func GetGitflowConfig(configuration *models.Configuration) *models.WorkflowConfi
 			Branches: []models.Branch{
 				masterBranchConfig,
 				developBranchConfig,
+				featureBranchConfig,
+				fixBranchConfig,
+				enhancementBranchConfig,
+				bugfixBranchConfig,
+				releaseBranchConfig,
+				hotfixBranchConfig,
 			},
 		},
 		Detail: "Workflow Description",
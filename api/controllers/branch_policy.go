@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+)
+
+//BranchPolicy represents the BranchPolicyController layer
+//It has an instance of a BranchPolicyService layer.
+type BranchPolicy struct {
+	Service services.BranchPolicyService
+}
+
+//NewBranchPolicyController initializes a BranchPolicyController
+func NewBranchPolicyController(sql storage.SQLStorage) *BranchPolicy {
+	return &BranchPolicy{
+		Service: services.NewBranchPolicyService(sql),
+	}
+}
+
+//Violations reports the branches of a repository which do not follow the workflow naming conventions.
+//It could returns
+//	200OK in case of a success procesing the search
+//	404NotFound in case of the non existance of the configuration
+//	500InternalServerError in case of an internal error procesing the search
+func (c *BranchPolicy) Violations(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	violations, err := c.Service.GetViolations(repoName)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong getting the branch violations for %s", repoName), err),
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusNotFound,
+			apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+		)
+		return
+	}
+
+	report := make([]interface{}, 0)
+	for _, v := range violations {
+		report = append(report, v.Marshall())
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"repository": repoName,
+		"total":      len(report),
+		"violations": report,
+	})
+}
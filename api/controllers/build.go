@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Build represents the BuildController layer
+//It has an instance of a BuildService layer.
+type Build struct {
+	Service services.BuildService
+}
+
+//NewBuildController initializes a BuildController
+func NewBuildController(sql storage.SQLStorage) *Build {
+	return &Build{
+		Service: services.NewBuildService(sql),
+	}
+}
+
+//Create triggers a build of a repository branch on the CI backend.
+//It could returns
+//	201Created in case of a success triggering the build
+//	400BadRequest in case of an error parsing the request payload
+//	404NotFound in case of the non existance of the configuration
+//	500InternalServerError in case of an internal error triggering the build
+func (c *Build) Create(ctx HTTPContext) {
+	var req models.PostBuildRequestPayload
+	if err := ctx.BindJSON(&req); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid build request payload"),
+		)
+		return
+	}
+
+	repoName := getRepoNamefromURL(ctx)
+	build, err := c.Service.Create(repoName, &req)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+			)
+		case services.ErrInvalidBuildRequest:
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError(err.Error()),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong triggering a build for %s", repoName), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, build.Marshall())
+}
+
+//Show returns a build of the repository with its status refreshed from the CI backend.
+//It could returns
+//	200OK in case of a success getting the build
+//	400BadRequest in case of an invalid build id
+//	404NotFound in case of the non existance of the configuration or the build
+//	500InternalServerError in case of an internal error getting the build
+func (c *Build) Show(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	id, parseErr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if parseErr != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid build id"),
+		)
+		return
+	}
+
+	build, err := c.Service.Get(repoName, id)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong getting the build %d of %s", id, repoName), err),
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusNotFound,
+			apierrors.NewNotFoundApiError(fmt.Sprintf("build %d for repository %s not found", id, repoName)),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, build.Marshall())
+}
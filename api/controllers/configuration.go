@@ -6,7 +6,10 @@ import (
 	"github.com/herbal828/ci_cd-api/api/services"
 	"github.com/herbal828/ci_cd-api/api/services/storage"
 	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"github.com/herbal828/ci_cd-api/api/utils/jsonpatch"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/jinzhu/gorm"
 )
@@ -14,9 +17,12 @@ import (
 //HTTPContext defines all the
 type HTTPContext interface {
 	BindJSON(interface{}) error
+	GetRawData() ([]byte, error)
 	GetHeader(string) string
+	Header(key string, value string)
 	JSON(int, interface{})
 	Param(key string) string
+	Query(key string) string
 }
 
 //Configuration represents the ConfigurationController layer
@@ -35,9 +41,12 @@ func NewConfigurationController(sql storage.SQLStorage) *Configuration {
 }
 
 //Create creates a new configuration for the given repository
+//An already configured repository is updated with the payload only if the request has an Idempotency-Key header
+//or the upsert=true query param.
 //It could returns
 //	200OK in case of a success processing the creation
-//	400BadRequest in case of an error parsing the request payload
+//	400BadRequest in case of an error parsing the request payload or an invalid field
+//	409Conflict in case of the repository is already configured
 //	500InternalServerError in case of an internal error procesing the creation
 func (c *Configuration) Create(ctx HTTPContext) {
 	var req models.PostRequestPayload
@@ -49,8 +58,22 @@ func (c *Configuration) Create(ctx HTTPContext) {
 		return
 	}
 
-	config, err := c.Service.Create(&req)
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, getValidationApiError(err))
+		return
+	}
+
+	upsert := ctx.Query("upsert") == "true" || ctx.GetHeader("Idempotency-Key") != ""
+
+	config, err := c.Service.Create(&req, upsert)
 	if err != nil {
+		if err == services.ErrConfigurationAlreadyExists {
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewConflictApiError(*config.ID),
+			)
+			return
+		}
 		ctx.JSON(
 			http.StatusInternalServerError,
 			apierrors.NewInternalServerApiError("something was wrong creating a new configuration", err),
@@ -58,9 +81,53 @@ func (c *Configuration) Create(ctx HTTPContext) {
 		return
 	}
 
+	ctx.Header("ETag", config.GetETag())
 	ctx.JSON(http.StatusOK, config.Marshall())
 }
 
+//List retrieves a page of the configurations which match the query filters.
+//The next page is linked through the Link header.
+//It could returns
+//	200OK in case of a success procesing the search
+//	400BadRequest in case of an invalid filter, sort, limit or cursor
+//	500InternalServerError in case of an internal error procesing the search
+func (c *Configuration) List(ctx HTTPContext) {
+	q, err := models.ParseConfigurationListQuery(ctx.Query)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError(err.Error()),
+		)
+		return
+	}
+
+	configs, next, err := c.Service.List(q)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			apierrors.NewInternalServerApiError("something was wrong listing the configurations", err),
+		)
+		return
+	}
+
+	results := make([]interface{}, 0)
+	for i := range configs {
+		results = append(results, configs[i].Marshall())
+	}
+
+	if next != "" {
+		ctx.Header("Link", fmt.Sprintf("</configurations?%s>; rel=\"next\"", q.Values(next).Encode()))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"results": results,
+		"paging": map[string]interface{}{
+			"limit":       q.Limit,
+			"next_cursor": next,
+		},
+	})
+}
+
 //Show retrieves the configuration for a given repository.
 //It could returns
 //	200OK in case of a success procesing the search
@@ -84,13 +151,18 @@ func (c *Configuration) Show(ctx HTTPContext) {
 		return
 	}
 
+	ctx.Header("ETag", config.GetETag())
 	ctx.JSON(http.StatusOK, config.Marshall())
 }
 
 //Update updates the configuration for a given repository.
+//The If-Match header must have the ETag of the configuration version to update.
 //It could returns
 //	200OK in case of a success procesing the update
+//	400BadRequest in case of an error parsing the request payload or an invalid field
 //	404NotFound in case of the non existance of the configuration
+//	412PreconditionFailed in case of the configuration was modified since the given ETag
+//	428PreconditionRequired in case of a request without If-Match header
 //	500InternalServerError in case of an internal error procesing the search
 func (c *Configuration) Update(ctx HTTPContext) {
 	var req models.PutRequestPayload
@@ -102,33 +174,128 @@ func (c *Configuration) Update(ctx HTTPContext) {
 		return
 	}
 
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, getValidationApiError(err))
+		return
+	}
+
 	repoName := getRepoNamefromURL(ctx)
 	req.Repository.Name = &repoName
 
-	config, err := c.Service.Update(&req)
+	config, err := c.Service.Update(&req, ctx.GetHeader("If-Match"))
 
 	if err != nil {
+		if apiErr := getPreconditionApiError(err); apiErr != nil {
+			ctx.JSON(apiErr.Status(), apiErr)
+			return
+		}
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+			)
+			return
+		}
 		ctx.JSON(
 			http.StatusInternalServerError,
 			apierrors.NewInternalServerApiError("something was wrong updating repository configuration", err),
 		)
+		return
 	}
 
+	ctx.Header("ETag", config.GetETag())
 	ctx.JSON(http.StatusOK, config.Marshall())
 
 }
 
+//Patch partially updates the configuration for a given repository.
+//The body is a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json)
+//of the PUT payload and the If-Match header must have the ETag of the configuration version to update.
+//It could returns
+//	200OK in case of a success procesing the update
+//	400BadRequest in case of a malformed patch, a patch which can not be applied or an invalid patched field
+//	404NotFound in case of the non existance of the configuration
+//	409Conflict in case of a failed JSON Patch test operation
+//	412PreconditionFailed in case of the configuration was modified since the given ETag
+//	415UnsupportedMediaType in case of a body which is not a supported patch
+//	428PreconditionRequired in case of a request without If-Match header
+//	500InternalServerError in case of an internal error procesing the update
+func (c *Configuration) Patch(ctx HTTPContext) {
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+
+	patch, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid configuration patch"),
+		)
+		return
+	}
+
+	repoName := getRepoNamefromURL(ctx)
+	config, err := c.Service.Patch(repoName, mediaType, patch, ctx.GetHeader("If-Match"))
+
+	if err != nil {
+		if apiErr := getPreconditionApiError(err); apiErr != nil {
+			ctx.JSON(apiErr.Status(), apiErr)
+			return
+		}
+		if validationErr, ok := err.(*models.ValidationError); ok {
+			ctx.JSON(http.StatusBadRequest, getValidationApiError(validationErr))
+			return
+		}
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+			)
+		case jsonpatch.ErrInvalidPatch:
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError(err.Error()),
+			)
+		case jsonpatch.ErrTestFailed:
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}),
+			)
+		case services.ErrUnsupportedPatchType:
+			ctx.JSON(
+				http.StatusUnsupportedMediaType,
+				apierrors.NewApiError(err.Error(), "unsupported_media_type", http.StatusUnsupportedMediaType, apierrors.CauseList{}),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError("something was wrong updating repository configuration", err),
+			)
+		}
+		return
+	}
+
+	ctx.Header("ETag", config.GetETag())
+	ctx.JSON(http.StatusOK, config.Marshall())
+}
+
 //Delete erases the configuration for a given repository from db, turn off Workflow and deletes continuous integration Jobs.
+//The If-Match header must have the ETag of the configuration version to delete.
 //It could returns
 //	204NoContent in case of a success procesing the delete
 //	404NotFound in case of the non existance of the configuration
+//	412PreconditionFailed in case of the configuration was modified since the given ETag
+//	428PreconditionRequired in case of a request without If-Match header
 //	500InternalServerError in case of an internal error processing the delete
 func (c *Configuration) Delete(ctx HTTPContext) {
 
 	repoName := getRepoNamefromURL(ctx)
-	err := c.Service.Delete(repoName)
+	err := c.Service.Delete(repoName, ctx.GetHeader("If-Match"))
 
 	if err != nil {
+		if apiErr := getPreconditionApiError(err); apiErr != nil {
+			ctx.JSON(apiErr.Status(), apiErr)
+			return
+		}
 		if err != gorm.ErrRecordNotFound {
 			ctx.JSON(
 				http.StatusInternalServerError,
@@ -149,6 +316,63 @@ func (c *Configuration) Delete(ctx HTTPContext) {
 	)
 }
 
+//getValidationApiError converts a ValidationError into an api error with a cause per invalid field.
+func getValidationApiError(err error) apierrors.ApiError {
+	causes := apierrors.CauseList{}
+	if validationErr, ok := err.(*models.ValidationError); ok {
+		for _, c := range validationErr.Causes {
+			causes = append(causes, c)
+		}
+	}
+	return apierrors.NewValidationApiError("invalid configuration request payload", "validation_error", causes)
+}
+
+//getPreconditionApiError maps the errors of the configuration version check, it returns nil for any other error.
+func getPreconditionApiError(err error) apierrors.ApiError {
+	switch err {
+	case services.ErrPreconditionRequired:
+		return apierrors.NewApiError(err.Error(), "precondition_required", http.StatusPreconditionRequired, apierrors.CauseList{})
+	case services.ErrPreconditionFailed:
+		return apierrors.NewApiError(err.Error(), "precondition_failed", http.StatusPreconditionFailed, apierrors.CauseList{})
+	}
+	return nil
+}
+
+//getRepoNamefromURL returns the ID of the configuration of the URL, composed by the repository owner and name.
+//Legacy URLs only have the repository name, which is resolved by the services.
 func getRepoNamefromURL(ctx HTTPContext) string {
-	return ctx.Param("repoName")
+	if repo := ctx.Param("repo"); repo != "" {
+		return models.GetConfigurationID(ctx.Param("owner"), repo)
+	}
+	return ctx.Param("owner")
+}
+
+//LegacySubResources are the sub resources of a configuration with a single segment.
+//Their legacy URLs, /configurations/:repoName/<sub resource>, match the owner/repo URL of the configuration.
+var LegacySubResources = map[string]bool{
+	"next-version": true,
+}
+
+//ResolveLegacyPath rewrites a legacy URL of a configuration sub resource, which identifies the configuration
+//by the bare repository name, into its owner/repo URL.
+//It returns false when the URL is not a legacy one or the repository name does not identify a configuration.
+func (c *Configuration) ResolveLegacyPath(path string) (string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) != 3 || parts[0] != "configurations" {
+		return "", false
+	}
+
+	//A legacy sub resource with a single segment is also an owner/repo URL, the configuration of that repository wins
+	if !strings.Contains(parts[2], "/") {
+		if _, err := c.Service.Get(models.GetConfigurationID(parts[1], parts[2])); err == nil {
+			return "", false
+		}
+	}
+
+	config, err := c.Service.Get(parts[1])
+	if err != nil || config.ID == nil || *config.ID == parts[1] {
+		return "", false
+	}
+
+	return fmt.Sprintf("/configurations/%s/%s", *config.ID, parts[2]), true
 }
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Coverage represents the CoverageController layer
+//It has an instance of a CoverageService layer.
+type Coverage struct {
+	Service services.CoverageService
+}
+
+//NewCoverageController initializes a CoverageController
+func NewCoverageController(sql storage.SQLStorage) *Coverage {
+	return &Coverage{
+		Service: services.NewCoverageService(sql),
+	}
+}
+
+//Report receives the coverage of a commit and checks it against the repository threshold.
+//It's a stand-in for the Codecov notifications which allows any coverage tool to report.
+//It could returns
+//	200OK in case of a success processing the report
+//	400BadRequest in case of an error parsing the request payload
+//	404NotFound in case of the non existance of the configuration
+//	500InternalServerError in case of an internal error procesing the report
+func (c *Coverage) Report(ctx HTTPContext) {
+	var req models.CoverageReport
+	if err := ctx.BindJSON(&req); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid coverage report payload"),
+		)
+		return
+	}
+
+	req.Source = "api"
+
+	repoName := getRepoNamefromURL(ctx)
+	evaluation, err := c.Service.Report(repoName, &req)
+	if err != nil {
+		handleCoverageReportError(ctx, repoName, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, evaluation)
+}
+
+//Codecov receives a Codecov webhook notification and checks the reported coverage against the repository threshold.
+//The notifications must be signed in the X-Codecov-Signature header with the Codecov webhook secret.
+//It could returns
+//	200OK in case of a success processing the notification
+//	400BadRequest in case of an error parsing the notification payload
+//	401Unauthorized in case of a missing or invalid notification signature
+//	404NotFound in case of the non existance of the configuration
+//	500InternalServerError in case of an internal error procesing the notification
+func (c *Coverage) Codecov(ctx HTTPContext) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid codecov notification payload"),
+		)
+		return
+	}
+
+	//Codecov signs the notifications with the hex HMAC-SHA256 of the body
+	if !utils.ValidSignatureSHA256(configs.GetCodecovWebhookSecret(), body, "sha256="+ctx.GetHeader("X-Codecov-Signature")) {
+		ctx.JSON(
+			http.StatusUnauthorized,
+			apierrors.NewUnauthorizedApiError("invalid codecov notification signature"),
+		)
+		return
+	}
+
+	var payload models.CodecovWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid codecov notification payload"),
+		)
+		return
+	}
+
+	repoName := models.GetConfigurationID(payload.Repo.Owner.Username, payload.Repo.Name)
+	evaluation, err := c.Service.Report(repoName, payload.ToCoverageReport())
+	if err != nil {
+		handleCoverageReportError(ctx, repoName, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, evaluation)
+}
+
+//Upload receives a Go coverprofile, LCOV or Cobertura report for a commit and checks it against the repository threshold.
+//The commit is given by the 'sha' query param and optionally the 'branch', 'base_branch', 'pull_request' and 'format' ones.
+//It could returns
+//	200OK in case of a success processing the report
+//	400BadRequest in case of an invalid or unsupported report
+//	404NotFound in case of the non existance of the configuration
+//	500InternalServerError in case of an internal error procesing the report
+func (c *Coverage) Upload(ctx HTTPContext) {
+	data, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid coverage report"),
+		)
+		return
+	}
+
+	upload := models.CoverageUpload{
+		Sha:        ctx.Query("sha"),
+		Branch:     ctx.Query("branch"),
+		BaseBranch: ctx.Query("base_branch"),
+		Format:     ctx.Query("format"),
+	}
+
+	if pr := ctx.Query("pull_request"); pr != "" {
+		number, err := strconv.Atoi(pr)
+		if err != nil {
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError("invalid pull_request param"),
+			)
+			return
+		}
+		upload.PullRequestNumber = &number
+	}
+
+	repoName := getRepoNamefromURL(ctx)
+	record, evaluation, err := c.Service.Upload(repoName, &upload, data)
+	if err != nil {
+		handleCoverageReportError(ctx, repoName, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"record":     record.Marshall(),
+		"evaluation": evaluation,
+	})
+}
+
+//History returns the coverage time series of the repository branches.
+//The results can be filtered by the 'branch' query param and the RFC3339 'from' and 'to' ones.
+//It could returns
+//	200OK in case of a success procesing the search
+//	400BadRequest in case of invalid dates
+//	404NotFound in case of the non existance of the configuration
+//	500InternalServerError in case of an internal error procesing the search
+func (c *Coverage) History(ctx HTTPContext) {
+	var dates [2]*time.Time
+	for i, param := range []string{"from", "to"} {
+		if value := ctx.Query(param); value != "" {
+			date, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				ctx.JSON(
+					http.StatusBadRequest,
+					apierrors.NewBadRequestApiError(fmt.Sprintf("invalid %s param, it must be a RFC3339 date", param)),
+				)
+				return
+			}
+			dates[i] = &date
+		}
+	}
+
+	repoName := getRepoNamefromURL(ctx)
+	history, err := c.Service.GetHistory(repoName, ctx.Query("branch"), dates[0], dates[1])
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong getting the coverage history for %s", repoName), err),
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusNotFound,
+			apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+func handleCoverageReportError(ctx HTTPContext, repoName string, err error) {
+	switch err {
+	case gorm.ErrRecordNotFound:
+		ctx.JSON(
+			http.StatusNotFound,
+			apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+		)
+	case services.ErrInvalidCoverageReport:
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError(err.Error()),
+		)
+	default:
+		ctx.JSON(
+			http.StatusInternalServerError,
+			apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong processing the coverage report for %s", repoName), err),
+		)
+	}
+}
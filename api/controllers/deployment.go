@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Deployment represents the DeploymentController layer
+//It has an instance of a DeploymentService layer.
+type Deployment struct {
+	Service services.DeploymentService
+}
+
+//NewDeploymentController initializes a DeploymentController
+func NewDeploymentController(sql storage.SQLStorage) *Deployment {
+	return &Deployment{
+		Service: services.NewDeploymentService(sql),
+	}
+}
+
+//Create deploys a ref into one of the repository environments.
+//It could returns
+//	201Created in case of a success creating the deployment
+//	400BadRequest in case of an error parsing the request payload
+//	404NotFound in case of the non existance of the configuration or the environment
+//	409Conflict in case of another deployment of the environment in progress
+//	500InternalServerError in case of an internal error creating the deployment
+func (c *Deployment) Create(ctx HTTPContext) {
+	var req models.PostDeploymentRequestPayload
+	if err := ctx.BindJSON(&req); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid deployment request payload"),
+		)
+		return
+	}
+
+	repoName := getRepoNamefromURL(ctx)
+	deployment, err := c.Service.Create(repoName, &req)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+			)
+		case services.ErrEnvironmentNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("environment %s for repository %s not found", req.Environment, repoName)),
+			)
+		case services.ErrDeploymentInProgress:
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong deploying %s", repoName), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, deployment.Marshall())
+}
+
+//Rollback re-deploys into an environment the commit of its previous successful deployment.
+//If the environment requires approvals, the rollback awaits them like any other deployment.
+//It could returns
+//	201Created in case of a success creating the rollback deployment
+//	404NotFound in case of the non existance of the configuration or the environment
+//	409Conflict in case of another deployment of the environment in progress or no deployment to roll back to
+//	500InternalServerError in case of an internal error creating the rollback deployment
+func (c *Deployment) Rollback(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	envName := ctx.Param("env")
+
+	deployment, err := c.Service.Rollback(repoName, envName)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+			)
+		case services.ErrEnvironmentNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("environment %s for repository %s not found", envName, repoName)),
+			)
+		case services.ErrDeploymentInProgress, services.ErrRollbackTargetNotFound:
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong rolling back %s of %s", envName, repoName), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, deployment.Marshall())
+}
+
+//Show returns a deployment with its status history.
+//It could returns
+//	200OK in case of a success getting the deployment
+//	400BadRequest in case of an invalid deployment id
+//	404NotFound in case of the non existance of the deployment
+//	500InternalServerError in case of an internal error getting the deployment
+func (c *Deployment) Show(ctx HTTPContext) {
+	id, ok := getDeploymentIDfromURL(ctx)
+	if !ok {
+		return
+	}
+
+	deployment, err := c.Service.Get(id)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong getting the deployment %d", id), err),
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusNotFound,
+			apierrors.NewNotFoundApiError(fmt.Sprintf("deployment %d not found", id)),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deployment.Marshall())
+}
+
+//AddStatus records the progress of a deployment reported by a CD backend.
+//It could returns
+//	200OK in case of a success recording the status
+//	400BadRequest in case of an error parsing the request payload or an invalid state
+//	404NotFound in case of the non existance of the deployment
+//	409Conflict in case of a finished deployment or a deployment awaiting approval
+//	500InternalServerError in case of an internal error recording the status
+func (c *Deployment) AddStatus(ctx HTTPContext) {
+	id, ok := getDeploymentIDfromURL(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.PostDeploymentStatusRequestPayload
+	if err := ctx.BindJSON(&req); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid deployment status request payload"),
+		)
+		return
+	}
+
+	deployment, err := c.Service.AddStatus(id, &req)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("deployment %d not found", id)),
+			)
+		case services.ErrInvalidDeploymentState:
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError(err.Error()),
+			)
+		case services.ErrDeploymentFinished, services.ErrDeploymentAwaitingApproval:
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong recording the status of the deployment %d", id), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deployment.Marshall())
+}
+
+//Review approves or rejects a deployment awaiting approval on behalf of an environment approver.
+//The approver is authenticated by the Github token of the Authorization header ('token <token>' or 'Bearer <token>').
+//It could returns
+//	200OK in case of a success recording the decision
+//	400BadRequest in case of an error parsing the request payload or an invalid decision
+//	401Unauthorized in case of a missing or invalid Github token
+//	403Forbidden in case of a user who is not an approver of the environment
+//	404NotFound in case of the non existance of the deployment
+//	409Conflict in case of a deployment not awaiting approval or an user who already approved it
+//	500InternalServerError in case of an internal error recording the decision
+func (c *Deployment) Review(ctx HTTPContext) {
+	id, ok := getDeploymentIDfromURL(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.PostApprovalRequestPayload
+	if err := ctx.BindJSON(&req); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid approval request payload"),
+		)
+		return
+	}
+
+	deployment, err := c.Service.Review(id, getTokenFromHeader(ctx), &req)
+	if err != nil {
+		switch err {
+		case services.ErrApproverNotAuthenticated:
+			ctx.JSON(
+				http.StatusUnauthorized,
+				apierrors.NewUnauthorizedApiError(err.Error()),
+			)
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("deployment %d not found", id)),
+			)
+		case services.ErrEnvironmentNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("environment of the deployment %d not found", id)),
+			)
+		case services.ErrInvalidApproval:
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError(err.Error()),
+			)
+		case services.ErrApproverNotAuthorized:
+			ctx.JSON(
+				http.StatusForbidden,
+				apierrors.NewForbiddenApiError(err.Error()),
+			)
+		case services.ErrDeploymentNotAwaitingApproval, services.ErrAlreadyApproved:
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong reviewing the deployment %d", id), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deployment.Marshall())
+}
+
+//getTokenFromHeader returns the token of the Authorization header, with the 'token' or 'Bearer' scheme.
+func getTokenFromHeader(ctx HTTPContext) string {
+	auth := ctx.GetHeader("Authorization")
+	for _, scheme := range []string{"token ", "Bearer "} {
+		if strings.HasPrefix(auth, scheme) {
+			return strings.TrimSpace(strings.TrimPrefix(auth, scheme))
+		}
+	}
+	return ""
+}
+
+//getDeploymentIDfromURL parses the deployment id of the URL.
+//It responds 400BadRequest when the id is not valid.
+func getDeploymentIDfromURL(ctx HTTPContext) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid deployment id"),
+		)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Hotfix represents the HotfixController layer
+//It has an instance of a HotfixService layer.
+type Hotfix struct {
+	Service services.HotfixService
+}
+
+//NewHotfixController initializes a HotfixController
+func NewHotfixController(sql storage.SQLStorage) *Hotfix {
+	return &Hotfix{
+		Service: services.NewHotfixService(sql),
+	}
+}
+
+//Create starts a new gitflow hotfix for the given repository from its latest version tag
+//It could returns
+//	201Created in case of a success processing the creation
+//	404NotFound in case of the non existance of the configuration
+//	409Conflict in case of another hotfix in progress or a repository without version tags
+//	500InternalServerError in case of an internal error procesing the creation
+func (c *Hotfix) Create(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	hotfix, err := c.Service.Create(repoName)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+			)
+		case services.ErrHotfixInProgress, services.ErrVersionTagNotFound:
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong creating a new hotfix for %s", repoName), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, hotfix.Marshall())
+}
+
+//Finish merges a gitflow hotfix into master and develop, resuming it from the last completed step.
+//It could returns
+//	200OK in case of a success finishing the hotfix
+//	202Accepted in case of a hotfix waiting for the required status checks
+//	404NotFound in case of the non existance of the configuration or the hotfix
+//	500InternalServerError in case of an internal error procesing the hotfix
+func (c *Hotfix) Finish(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	version := ctx.Param("version")
+
+	hotfix, err := c.Service.Finish(repoName, version)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("hotfix %s for repository %s not found", version, repoName)),
+			)
+		case services.ErrRequiredChecksPending:
+			ctx.JSON(http.StatusAccepted, hotfix.Marshall())
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong finishing the hotfix %s for %s", version, repoName), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, hotfix.Marshall())
+}
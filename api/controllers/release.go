@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Release represents the ReleaseController layer
+//It has an instance of a ReleaseService layer.
+type Release struct {
+	Service          services.ReleaseService
+	ChangelogService services.ChangelogService
+}
+
+//NewReleaseController initializes a ReleaseController
+func NewReleaseController(sql storage.SQLStorage) *Release {
+	return &Release{
+		Service:          services.NewReleaseService(sql),
+		ChangelogService: services.NewChangelogService(sql),
+	}
+}
+
+//Create starts a new gitflow release for the given repository
+//It could returns
+//	201Created in case of a success processing the creation
+//	400BadRequest in case of an error parsing the request payload or an invalid version
+//	404NotFound in case of the non existance of the configuration
+//	409Conflict in case of another release in progress
+//	500InternalServerError in case of an internal error procesing the creation
+func (c *Release) Create(ctx HTTPContext) {
+	var req models.PostReleaseRequestPayload
+	if err := ctx.BindJSON(&req); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid release request payload"),
+		)
+		return
+	}
+
+	repoName := getRepoNamefromURL(ctx)
+	release, err := c.Service.Create(repoName, &req)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+			)
+		case services.ErrInvalidReleaseVersion:
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError(err.Error()),
+			)
+		case services.ErrReleaseInProgress:
+			ctx.JSON(
+				http.StatusConflict,
+				apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}),
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong creating a new release for %s", repoName), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, release.Marshall())
+}
+
+//Finish completes a gitflow release, resuming it from the last completed step.
+//It could returns
+//	200OK in case of a success finishing the release
+//	202Accepted in case of a release waiting for the required status checks
+//	404NotFound in case of the non existance of the configuration or the release
+//	500InternalServerError in case of an internal error procesing the release
+func (c *Release) Finish(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	version := ctx.Param("version")
+
+	release, err := c.Service.Finish(repoName, version)
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError(fmt.Sprintf("release %s for repository %s not found", version, repoName)),
+			)
+		case services.ErrRequiredChecksPending:
+			ctx.JSON(http.StatusAccepted, release.Marshall())
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong finishing the release %s for %s", version, repoName), err),
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, release.Marshall())
+}
+
+//Changelog builds the changelog of a release, grouping its pull requests and commits by type or label.
+//It could returns
+//	200OK in case of a success building the changelog
+//	404NotFound in case of the non existance of the configuration or the release
+//	500InternalServerError in case of an internal error building the changelog
+func (c *Release) Changelog(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	version := ctx.Param("version")
+
+	changelog, err := c.ChangelogService.GetReleaseChangelog(repoName, version)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong building the changelog of release %s for %s", version, repoName), err),
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusNotFound,
+			apierrors.NewNotFoundApiError(fmt.Sprintf("release %s for repository %s not found", version, repoName)),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &struct {
+		*models.Changelog
+		Markdown string `json:"markdown"`
+	}{
+		changelog,
+		changelog.Markdown(),
+	})
+}
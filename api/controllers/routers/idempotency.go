@@ -0,0 +1,102 @@
+package routers
+
+import (
+	"bytes"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//responseRecorder keeps a copy of the response body written by the handlers.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+//Idempotency makes the mutating requests with an Idempotency-Key header safe to retry.
+//The first request with a key is processed and its response stored, the retries with the same key replay it
+//along with its ETag, Location and Link headers, and the ones received while the first is in progress get a 409 Conflict.
+//Responses of server errors are not stored, so those requests can be retried.
+//If the response can not be stored, the key is released so it does not stay in progress.
+func Idempotency(s services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(models.IdempotencyKeyHeader)
+
+		//Unmatched requests are skipped, the legacy ones are handled once they are resolved
+		if key == "" || c.FullPath() == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, apierrors.NewBadRequestApiError("invalid request body"))
+			return
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		k, err := s.Start(key, c.Request.Method, c.Request.URL.Path, body)
+		if err != nil {
+			switch err {
+			case services.ErrIdempotencyKeyInProgress:
+				c.AbortWithStatusJSON(http.StatusConflict, apierrors.NewApiError(err.Error(), "conflict_error", http.StatusConflict, apierrors.CauseList{}))
+			case services.ErrIdempotencyKeyMismatch:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, apierrors.NewApiError(err.Error(), "unprocessable_entity", http.StatusUnprocessableEntity, apierrors.CauseList{}))
+			default:
+				c.AbortWithStatusJSON(http.StatusInternalServerError, apierrors.NewInternalServerApiError("something was wrong checking the idempotency key", err))
+			}
+			return
+		}
+
+		//A retry replays the stored response
+		if k.IsCompleted() {
+			for name, value := range k.GetResponseHeaders() {
+				c.Header(name, value)
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(k.ResponseStatus, "application/json; charset=utf-8", []byte(k.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		if c.Writer.Status() < http.StatusInternalServerError {
+			err = s.Complete(k, c.Writer.Status(), recorder.body.Bytes(), c.Writer.Header())
+			if err == nil {
+				return
+			}
+			log.Printf("error completing idempotency key %s: %v", key, err)
+		}
+
+		if err := s.Release(k); err != nil {
+			log.Printf("error releasing idempotency key %s: %v", key, err)
+		}
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
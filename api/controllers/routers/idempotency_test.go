@@ -0,0 +1,149 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/stretchr/testify/assert"
+)
+
+//fakeIdempotencyService keeps the idempotency keys in memory.
+type fakeIdempotencyService struct {
+	keys         map[string]*models.IdempotencyKey
+	failComplete bool
+}
+
+func (s *fakeIdempotencyService) Start(key string, method string, path string, body []byte) (*models.IdempotencyKey, error) {
+	k := models.NewIdempotencyKey(key, method, path, body)
+	existing, ok := s.keys[key]
+	if !ok {
+		s.keys[key] = k
+		return k, nil
+	}
+	if existing.Fingerprint != k.Fingerprint {
+		return nil, services.ErrIdempotencyKeyMismatch
+	}
+	if !existing.IsCompleted() {
+		return nil, services.ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+func (s *fakeIdempotencyService) Complete(k *models.IdempotencyKey, status int, body []byte, header http.Header) error {
+	if s.failComplete {
+		return errors.New("error updating idempotency key")
+	}
+	k.Complete(status, body, header)
+	return nil
+}
+
+func (s *fakeIdempotencyService) Release(k *models.IdempotencyKey) error {
+	delete(s.keys, k.Key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		key  string
+		body string
+	}
+
+	tests := []struct {
+		name         string
+		inProgress   string
+		failComplete bool
+		status       int
+		requests     []request
+		wantCodes    []int
+		wantHandled  int
+		wantReplayed string
+	}{
+		{
+			name:        "test - requests without key are always handled",
+			status:      http.StatusOK,
+			requests:    []request{{body: `{"a":1}`}, {body: `{"a":1}`}},
+			wantCodes:   []int{http.StatusOK, http.StatusOK},
+			wantHandled: 2,
+		},
+		{
+			name:         "test - retry replays the first response",
+			status:       http.StatusOK,
+			requests:     []request{{key: "k1", body: `{"a":1}`}, {key: "k1", body: `{"a":1}`}},
+			wantCodes:    []int{http.StatusOK, http.StatusOK},
+			wantHandled:  1,
+			wantReplayed: "true",
+		},
+		{
+			name:        "test - key reused by a different request",
+			status:      http.StatusOK,
+			requests:    []request{{key: "k1", body: `{"a":1}`}, {key: "k1", body: `{"a":2}`}},
+			wantCodes:   []int{http.StatusOK, http.StatusUnprocessableEntity},
+			wantHandled: 1,
+		},
+		{
+			name:        "test - concurrent duplicate",
+			status:      http.StatusOK,
+			inProgress:  "k1",
+			requests:    []request{{key: "k1", body: `{"a":1}`}},
+			wantCodes:   []int{http.StatusConflict},
+			wantHandled: 0,
+		},
+		{
+			name:        "test - server errors are not replayed",
+			status:      http.StatusInternalServerError,
+			requests:    []request{{key: "k1", body: `{"a":1}`}, {key: "k1", body: `{"a":1}`}},
+			wantCodes:   []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			wantHandled: 2,
+		},
+		{
+			name:         "test - keys which can not be completed are released",
+			failComplete: true,
+			status:       http.StatusOK,
+			requests:     []request{{key: "k1", body: `{"a":1}`}, {key: "k1", body: `{"a":1}`}},
+			wantCodes:    []int{http.StatusOK, http.StatusOK},
+			wantHandled:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeIdempotencyService{keys: map[string]*models.IdempotencyKey{}, failComplete: tt.failComplete}
+			if tt.inProgress != "" {
+				s.keys[tt.inProgress] = models.NewIdempotencyKey(tt.inProgress, http.MethodPost, "/configurations", []byte(`{"a":1}`))
+			}
+
+			handled := 0
+			r := gin.New()
+			r.Use(Idempotency(s))
+			r.POST("/configurations", func(c *gin.Context) {
+				handled++
+				c.Header("ETag", `"1"`)
+				c.JSON(tt.status, map[string]interface{}{"handled": handled})
+			})
+
+			var w *httptest.ResponseRecorder
+			for i, rq := range tt.requests {
+				w = httptest.NewRecorder()
+				req, _ := http.NewRequest(http.MethodPost, "/configurations", strings.NewReader(rq.body))
+				if rq.key != "" {
+					req.Header.Set(models.IdempotencyKeyHeader, rq.key)
+				}
+				r.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.wantCodes[i], w.Code)
+			}
+
+			assert.Equal(t, tt.wantHandled, handled)
+			assert.Equal(t, tt.wantReplayed, w.Header().Get("Idempotent-Replayed"))
+			if tt.wantReplayed != "" {
+				assert.JSONEq(t, `{"handled":1}`, w.Body.String())
+				assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
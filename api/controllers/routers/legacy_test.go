@@ -0,0 +1,105 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbal828/ci_cd-api/api/controllers"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+//fakeConfigurationService resolves the configurations by their ID or, for the legacy IDs, by the repository name.
+type fakeConfigurationService struct {
+	configs []models.Configuration
+}
+
+func (s *fakeConfigurationService) Get(id string) (*models.Configuration, error) {
+	for i, c := range s.configs {
+		if *c.ID == id || *c.RepositoryName == id {
+			return &s.configs[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeConfigurationService) Create(r *models.PostRequestPayload, upsert bool) (*models.Configuration, error) {
+	return nil, nil
+}
+
+func (s *fakeConfigurationService) List(q *models.ConfigurationListQuery) ([]models.Configuration, string, error) {
+	return nil, "", nil
+}
+
+func (s *fakeConfigurationService) Update(r *models.PutRequestPayload, ifMatch string) (*models.Configuration, error) {
+	return nil, nil
+}
+
+func (s *fakeConfigurationService) Patch(id string, mediaType string, patch []byte, ifMatch string) (*models.Configuration, error) {
+	return nil, nil
+}
+
+func (s *fakeConfigurationService) Delete(id string, ifMatch string) error {
+	return nil
+}
+
+func TestLegacySubResourcePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "test - legacy next version",
+			path: "/configurations/api/next-version",
+			want: "next-version acme/api",
+		},
+		{
+			name: "test - owner/repo next version",
+			path: "/configurations/acme/api/next-version",
+			want: "next-version acme/api",
+		},
+		{
+			name: "test - repository named as the sub resource",
+			path: "/configurations/acme/next-version",
+			want: "show acme/next-version",
+		},
+		{
+			name: "test - unknown legacy repository",
+			path: "/configurations/web/next-version",
+			want: "show web/next-version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := &controllers.Configuration{
+				Service: &fakeConfigurationService{configs: []models.Configuration{
+					{ID: utils.Stringify("acme/api"), RepositoryName: utils.Stringify("api")},
+					{ID: utils.Stringify("acme/next-version"), RepositoryName: utils.Stringify("next-version")},
+				}},
+			}
+
+			r := gin.New()
+			r.GET("/configurations/:owner/:repo", func(c *gin.Context) {
+				if controllers.LegacySubResources[c.Param("repo")] && handleLegacyPath(r, ct, c) {
+					return
+				}
+				c.String(http.StatusOK, "show "+c.Param("owner")+"/"+c.Param("repo"))
+			})
+			r.GET("/configurations/:owner/:repo/next-version", func(c *gin.Context) {
+				c.String(http.StatusOK, "next-version "+c.Param("owner")+"/"+c.Param("repo"))
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Body.String())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package routers
 
 import (
 	"github.com/herbal828/ci_cd-api/api/controllers"
+	"github.com/herbal828/ci_cd-api/api/services"
 	"github.com/herbal828/ci_cd-api/api/services/storage"
 	"net/http"
 
@@ -17,31 +18,190 @@ var (
 func Route() *gin.Engine {
 	r := gin.Default()
 
+	//Retries of the mutating requests with an Idempotency-Key header replay the first response
+	r.Use(Idempotency(services.NewIdempotencyService(SQLConnection)))
+
 	r.GET("/ping", func(c *gin.Context) {
 		c.String(http.StatusOK, "pong")
 	})
 
 	ct := controllers.NewConfigurationController(SQLConnection)
+	wh := controllers.NewWebhookController(SQLConnection)
+	bp := controllers.NewBranchPolicyController(SQLConnection)
+	rl := controllers.NewReleaseController(SQLConnection)
+	hf := controllers.NewHotfixController(SQLConnection)
+	vs := controllers.NewVersioningController(SQLConnection)
+	cv := controllers.NewCoverageController(SQLConnection)
+	bd := controllers.NewBuildController(SQLConnection)
+	dp := controllers.NewDeploymentController(SQLConnection)
 
 	//POST to /configurations performs a release process configuration create
 	r.POST("/configurations", func(c *gin.Context) {
 		ct.Create(c)
 	})
 
-	//GET to /configurations/:repoName performs a release process configuration get
-	r.GET("/configurations/:repoName", func(c *gin.Context) {
+	//GET to /configurations lists the release process configurations with cursor pagination
+	r.GET("/configurations", func(c *gin.Context) {
+		ct.List(c)
+	})
+
+	//GET to /configurations/:owner/:repo performs a release process configuration get.
+	//The legacy GET to /configurations/:repoName/next-version matches it too, so it is resolved first
+	r.GET("/configurations/:owner/:repo", func(c *gin.Context) {
+		if controllers.LegacySubResources[c.Param("repo")] && handleLegacyPath(r, ct, c) {
+			return
+		}
 		ct.Show(c)
 	})
 
-	//PUT to /configurations/:repoName performs a release process configuration update
-	r.PUT("/configurations/:repoName", func(c *gin.Context) {
+	//PUT to /configurations/:owner/:repo performs a release process configuration update
+	r.PUT("/configurations/:owner/:repo", func(c *gin.Context) {
 		ct.Update(c)
 	})
 
-	//DELETE to /configurations/:repoName performs a release process configuration delete
-	r.DELETE("/configurations/:repoName", func(c *gin.Context) {
+	//PATCH to /configurations/:owner/:repo performs a release process configuration partial update
+	r.PATCH("/configurations/:owner/:repo", func(c *gin.Context) {
+		ct.Patch(c)
+	})
+
+	//DELETE to /configurations/:owner/:repo performs a release process configuration delete
+	r.DELETE("/configurations/:owner/:repo", func(c *gin.Context) {
 		ct.Delete(c)
 	})
 
+	//GET, PUT, PATCH and DELETE to /configurations/:repoName are the legacy routes which identify
+	//the configuration by the bare repository name
+	r.GET("/configurations/:owner", func(c *gin.Context) {
+		ct.Show(c)
+	})
+	r.PUT("/configurations/:owner", func(c *gin.Context) {
+		ct.Update(c)
+	})
+	r.PATCH("/configurations/:owner", func(c *gin.Context) {
+		ct.Patch(c)
+	})
+	r.DELETE("/configurations/:owner", func(c *gin.Context) {
+		ct.Delete(c)
+	})
+
+	//GET to /configurations/:owner/:repo/branches/violations reports the branches which do not follow the workflow naming conventions
+	r.GET("/configurations/:owner/:repo/branches/violations", func(c *gin.Context) {
+		bp.Violations(c)
+	})
+
+	//POST to /configurations/:owner/:repo/releases starts a new gitflow release
+	r.POST("/configurations/:owner/:repo/releases", func(c *gin.Context) {
+		rl.Create(c)
+	})
+
+	//POST to /configurations/:owner/:repo/releases/:version/finish merges, tags and publishes a gitflow release
+	r.POST("/configurations/:owner/:repo/releases/:version/finish", func(c *gin.Context) {
+		rl.Finish(c)
+	})
+
+	//GET to /configurations/:owner/:repo/releases/:version/changelog builds the changelog of a gitflow release
+	r.GET("/configurations/:owner/:repo/releases/:version/changelog", func(c *gin.Context) {
+		rl.Changelog(c)
+	})
+
+	//POST to /configurations/:owner/:repo/hotfixes starts a new gitflow hotfix from the latest version tag
+	r.POST("/configurations/:owner/:repo/hotfixes", func(c *gin.Context) {
+		hf.Create(c)
+	})
+
+	//POST to /configurations/:owner/:repo/hotfixes/:version/finish merges a gitflow hotfix into master and develop
+	r.POST("/configurations/:owner/:repo/hotfixes/:version/finish", func(c *gin.Context) {
+		hf.Finish(c)
+	})
+
+	//GET to /configurations/:owner/:repo/next-version computes the next semantic version of a branch from its commits
+	r.GET("/configurations/:owner/:repo/next-version", func(c *gin.Context) {
+		vs.NextVersion(c)
+	})
+
+	//POST to /configurations/:owner/:repo/coverage uploads a Go coverprofile, LCOV or Cobertura report of a commit
+	r.POST("/configurations/:owner/:repo/coverage", func(c *gin.Context) {
+		cv.Upload(c)
+	})
+
+	//POST to /configurations/:owner/:repo/coverage/reports checks the coverage of a commit against the repository threshold
+	r.POST("/configurations/:owner/:repo/coverage/reports", func(c *gin.Context) {
+		cv.Report(c)
+	})
+
+	//GET to /configurations/:owner/:repo/coverage/history returns the coverage time series of the repository branches
+	r.GET("/configurations/:owner/:repo/coverage/history", func(c *gin.Context) {
+		cv.History(c)
+	})
+
+	//POST to /configurations/:owner/:repo/builds triggers a build of a repository branch on the CI backend
+	r.POST("/configurations/:owner/:repo/builds", func(c *gin.Context) {
+		bd.Create(c)
+	})
+
+	//GET to /configurations/:owner/:repo/builds/:id returns a build with its status refreshed from the CI backend
+	r.GET("/configurations/:owner/:repo/builds/:id", func(c *gin.Context) {
+		bd.Show(c)
+	})
+
+	//POST to /configurations/:owner/:repo/deployments deploys a ref into one of the repository environments
+	r.POST("/configurations/:owner/:repo/deployments", func(c *gin.Context) {
+		dp.Create(c)
+	})
+
+	//POST to /configurations/:owner/:repo/deployments/:env/rollback re-deploys the previous successful deployment of an environment
+	r.POST("/configurations/:owner/:repo/deployments/:env/rollback", func(c *gin.Context) {
+		dp.Rollback(c)
+	})
+
+	//GET to /deployments/:id returns a deployment with its status history
+	r.GET("/deployments/:id", func(c *gin.Context) {
+		dp.Show(c)
+	})
+
+	//POST to /deployments/:id/statuses records the progress of a deployment reported by a CD backend
+	r.POST("/deployments/:id/statuses", func(c *gin.Context) {
+		dp.AddStatus(c)
+	})
+
+	//POST to /deployments/:id/approvals approves or rejects a deployment awaiting approval
+	r.POST("/deployments/:id/approvals", func(c *gin.Context) {
+		dp.Review(c)
+	})
+
+	//POST to /webhooks/github receives the events delivered by the repositories webhooks
+	r.POST("/webhooks/github", func(c *gin.Context) {
+		wh.Github(c)
+	})
+
+	//POST to /webhooks/codecov receives the Codecov notifications
+	r.POST("/webhooks/codecov", func(c *gin.Context) {
+		cv.Codecov(c)
+	})
+
+	//The legacy routes of the configuration sub resources are served by their owner/repo routes.
+	//A request is resolved only once, so a repository named as its owner can not loop.
+	r.NoRoute(func(c *gin.Context) {
+		handleLegacyPath(r, ct, c)
+	})
+
 	return r
 }
+
+//handleLegacyPath serves a legacy configuration sub resource URL through its owner/repo route.
+//It returns false if the URL could not be resolved, then the request is left to the caller.
+func handleLegacyPath(r *gin.Engine, ct *controllers.Configuration, c *gin.Context) bool {
+	if c.GetHeader("X-Legacy-Path") != "" {
+		return false
+	}
+
+	path, ok := ct.ResolveLegacyPath(c.Request.URL.Path)
+	if !ok {
+		return false
+	}
+
+	c.Request.Header.Set("X-Legacy-Path", c.Request.URL.Path)
+	c.Request.URL.Path = path
+	r.HandleContext(c)
+	return true
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Versioning represents the VersioningController layer
+//It has an instance of a VersioningService layer.
+type Versioning struct {
+	Service services.VersioningService
+}
+
+//NewVersioningController initializes a VersioningController
+func NewVersioningController(sql storage.SQLStorage) *Versioning {
+	return &Versioning{
+		Service: services.NewVersioningService(sql),
+	}
+}
+
+//NextVersion computes the next semantic version of a repository branch given by the 'branch' query param.
+//It could returns
+//	200OK in case of a success computing the version
+//	404NotFound in case of the non existance of the configuration
+//	500InternalServerError in case of an internal error computing the version
+func (c *Versioning) NextVersion(ctx HTTPContext) {
+	repoName := getRepoNamefromURL(ctx)
+	nv, err := c.Service.GetNextVersion(repoName, ctx.Query("branch"))
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError(fmt.Sprintf("something was wrong computing the next version for %s", repoName), err),
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusNotFound,
+			apierrors.NewNotFoundApiError(fmt.Sprintf("configuration for repository %s not found", repoName)),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nv)
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"github.com/herbal828/ci_cd-api/api/utils/apierrors"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Webhook represents the WebhookController layer
+//It receives the events delivered by the repositories webhooks.
+type Webhook struct {
+	BranchPolicyService services.BranchPolicyService
+	DeploymentService   services.DeploymentService
+}
+
+//NewWebhookController initializes a WebhookController
+func NewWebhookController(sql storage.SQLStorage) *Webhook {
+	return &Webhook{
+		BranchPolicyService: services.NewBranchPolicyService(sql),
+		DeploymentService:   services.NewDeploymentService(sql),
+	}
+}
+
+//Github processes an event delivered by a repository webhook.
+//The events must be signed in the X-Hub-Signature-256 header with the webhook secret.
+//It could returns
+//	200OK in case of a success processing the event
+//	400BadRequest in case of an error parsing the event payload
+//	401Unauthorized in case of a missing or invalid event signature
+//	404NotFound in case of the non existance of the repository configuration
+//	500InternalServerError in case of an internal error procesing the event
+func (c *Webhook) Github(ctx HTTPContext) {
+	event := ctx.GetHeader("X-GitHub-Event")
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			apierrors.NewBadRequestApiError("invalid github event payload"),
+		)
+		return
+	}
+
+	//Only the events signed by Github are processed
+	if !utils.ValidSignatureSHA256(configs.GetWebhookSecret(), body, ctx.GetHeader("X-Hub-Signature-256")) {
+		ctx.JSON(
+			http.StatusUnauthorized,
+			apierrors.NewUnauthorizedApiError("invalid github event signature"),
+		)
+		return
+	}
+
+	switch event {
+	case "push", "create":
+		var payload models.GithubWebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError("invalid github event payload"),
+			)
+			return
+		}
+
+		violation, err := c.BranchPolicyService.CheckBranch(event, &payload)
+		if err != nil {
+			if err != gorm.ErrRecordNotFound {
+				ctx.JSON(
+					http.StatusInternalServerError,
+					apierrors.NewInternalServerApiError("something was wrong checking the branch policy", err),
+				)
+				return
+			}
+			ctx.JSON(
+				http.StatusNotFound,
+				apierrors.NewNotFoundApiError("configuration for repository "+payload.Repository.FullName+" not found"),
+			)
+			return
+		}
+
+		if violation != nil {
+			ctx.JSON(http.StatusOK, violation.Marshall())
+			return
+		}
+
+	case "deployment_status":
+		var payload models.GithubDeploymentStatusPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			ctx.JSON(
+				http.StatusBadRequest,
+				apierrors.NewBadRequestApiError("invalid github event payload"),
+			)
+			return
+		}
+
+		deployment, err := c.DeploymentService.HandleStatusEvent(&payload)
+		if err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				apierrors.NewInternalServerApiError("something was wrong recording the deployment status", err),
+			)
+			return
+		}
+
+		if deployment != nil {
+			ctx.JSON(http.StatusOK, deployment.Marshall())
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"event":  event,
+		"status": "processed",
+	})
+}
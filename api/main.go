@@ -26,7 +26,12 @@ func main() {
 		fmt.Println("There was an error stablishing the MySQL connection")
 	}
 
-	sql.Client.AutoMigrate(&models.Configuration{}, &models.RequireStatusCheck{})
+	sql.Client.AutoMigrate(&models.Configuration{}, &models.RequireStatusCheck{}, &models.BranchViolation{}, &models.Release{}, &models.Hotfix{}, &models.CoverageRecord{}, &models.Build{}, &models.Environment{}, &models.Deployment{}, &models.DeploymentStatus{}, &models.EnvironmentApprover{}, &models.Approval{}, &models.IdempotencyKey{})
+
+	//Configurations created before the owner/name IDs are moved to them
+	if err := sql.MigrateConfigurationIDs(); err != nil {
+		fmt.Println("There was an error migrating the configuration ids")
+	}
 
 	routers.SQLConnection = sql
 
@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+//Decisions of the deployment approvals
+const (
+	ApprovalDecisionApprove = "approve"
+	ApprovalDecisionReject  = "reject"
+)
+
+//PostApprovalRequestPayload represents the request body needed to approve or reject a deployment.
+//The user is never taken from the body, it is the owner of the Github token the request is authenticated with.
+type PostApprovalRequestPayload struct {
+	User     string `json:"-"`
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+//Approval is the decision of an environment approver about a deployment.
+//Approvals are never deleted, they are the audit trail of the deployment sign-off.
+type Approval struct {
+	ID           *uint64 `gorm:"primary_key"`
+	DeploymentID *uint64
+	User         string
+	Decision     string
+	Reason       string
+	ExpiresAt    time.Time
+
+	//GORM date attributes
+	CreatedAt time.Time
+}
+
+//NewApproval returns a new Approval of the deployment which expires after the environment approval TTL.
+func NewApproval(d *Deployment, env *Environment, r *PostApprovalRequestPayload) *Approval {
+	now := time.Now().UTC()
+	return &Approval{
+		DeploymentID: d.ID,
+		User:         r.User,
+		Decision:     r.Decision,
+		Reason:       r.Reason,
+		ExpiresAt:    now.Add(env.GetApprovalTTL()),
+		CreatedAt:    now,
+	}
+}
+
+//IsValid reports if the approval is an approve decision which is not expired at the given time.
+func (a *Approval) IsValid(now time.Time) bool {
+	return a.Decision == ApprovalDecisionApprove && now.Before(a.ExpiresAt)
+}
+
+//CountValidApprovals returns the number of users with a valid approval of the deployment at the given time.
+func (d *Deployment) CountValidApprovals(now time.Time) int {
+	users := make(map[string]bool)
+	for _, a := range d.Approvals {
+		if a.IsValid(now) {
+			users[a.User] = true
+		}
+	}
+	return len(users)
+}
+
+//HasValidApproval reports if the user has a valid approval of the deployment at the given time.
+func (d *Deployment) HasValidApproval(user string, now time.Time) bool {
+	for _, a := range d.Approvals {
+		if a.User == user && a.IsValid(now) {
+			return true
+		}
+	}
+	return false
+}
+
+//Marshall converts the Approval struct into a readable JSON interface.
+func (a *Approval) Marshall() interface{} {
+	return &struct {
+		User      string    `json:"user"`
+		Decision  string    `json:"decision"`
+		Reason    string    `json:"reason"`
+		ExpiresAt time.Time `json:"expires_at"`
+		CreatedAt time.Time `json:"created_at"`
+	}{
+		a.User,
+		a.Decision,
+		a.Reason,
+		a.ExpiresAt,
+		a.CreatedAt,
+	}
+}
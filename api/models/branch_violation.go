@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+//BranchViolation represents a branch pushed to a repository whose name does not follow the workflow conventions.
+type BranchViolation struct {
+	ID              *uint64 `gorm:"primary_key"`
+	ConfigurationID *string
+	Branch          string
+	Event           string
+	Sender          string
+	Reason          string
+
+	//GORM date attributes
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//Marshall converts the BranchViolation struct into a readable JSON interface.
+func (v *BranchViolation) Marshall() interface{} {
+	return &struct {
+		Branch    string    `json:"branch"`
+		Event     string    `json:"event"`
+		Sender    string    `json:"sender"`
+		Reason    string    `json:"reason"`
+		CreatedAt time.Time `json:"created_at"`
+	}{
+		v.Branch,
+		v.Event,
+		v.Sender,
+		v.Reason,
+		v.CreatedAt,
+	}
+}
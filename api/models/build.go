@@ -0,0 +1,132 @@
+package models
+
+import "time"
+
+//Build states, shared by every continuous integration backend
+const (
+	BuildStatePending = "pending"
+	BuildStateRunning = "running"
+	BuildStateSuccess = "success"
+	BuildStateFailure = "failure"
+	BuildStateError   = "error"
+)
+
+//BuildStatus represents the status of a build reported by the continuous integration backend.
+type BuildStatus struct {
+	ID         string     `json:"id"`
+	State      string     `json:"state"`
+	LogURL     string     `json:"log_url"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+//IsFinished reports if the build reached a final state.
+func (b *BuildStatus) IsFinished() bool {
+	return b.State == BuildStateSuccess || b.State == BuildStateFailure || b.State == BuildStateError
+}
+
+//BuildStatusContext is the commit status context used to report the builds of the repositories.
+//Repositories which gate their merges on the builds must require it.
+const BuildStatusContext = "continuous-integration"
+
+//PostBuildRequestPayload represents the request body needed to trigger a build of a repository branch.
+type PostBuildRequestPayload struct {
+	Branch string `json:"branch"`
+	Sha    string `json:"sha"`
+}
+
+//Build represents a build of a repository branch triggered from the API.
+type Build struct {
+	ID              *uint64 `gorm:"primary_key"`
+	ConfigurationID *string
+	ExternalID      string
+	Branch          string
+	Sha             string
+	State           string
+	LogURL          string
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+
+	//GORM date attributes
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//NewBuild returns a new Build for the given configuration with the status reported by the CI backend.
+func NewBuild(config *Configuration, r *PostBuildRequestPayload, status *BuildStatus) *Build {
+	b := &Build{
+		ConfigurationID: config.ID,
+		Branch:          r.Branch,
+		Sha:             r.Sha,
+	}
+	b.SetStatus(status)
+	return b
+}
+
+//SetStatus updates the build with the status reported by the CI backend.
+//It reports if the build state has changed.
+func (b *Build) SetStatus(status *BuildStatus) bool {
+	changed := b.State != status.State
+
+	if status.ID != "" {
+		b.ExternalID = status.ID
+	}
+	if status.LogURL != "" {
+		b.LogURL = status.LogURL
+	}
+	if status.StartedAt != nil {
+		b.StartedAt = status.StartedAt
+	}
+	if status.FinishedAt != nil {
+		b.FinishedAt = status.FinishedAt
+	}
+	b.State = status.State
+
+	return changed
+}
+
+//IsFinished reports if the build reached a final state.
+func (b *Build) IsFinished() bool {
+	return (&BuildStatus{State: b.State}).IsFinished()
+}
+
+//Description returns a human readable description of the build state.
+func (b *Build) Description() string {
+	switch b.State {
+	case BuildStateRunning:
+		return "The build is running"
+	case BuildStateSuccess:
+		return "The build succeeded"
+	case BuildStateFailure:
+		return "The build failed"
+	case BuildStateError:
+		return "The build could not be completed"
+	default:
+		return "The build is waiting to start"
+	}
+}
+
+//Marshall converts the Build struct into a readable JSON interface.
+func (b *Build) Marshall() interface{} {
+	return &struct {
+		ID         *uint64    `json:"id"`
+		Branch     string     `json:"branch"`
+		Sha        string     `json:"sha"`
+		State      string     `json:"state"`
+		LogURL     string     `json:"log_url"`
+		StartedAt  *time.Time `json:"started_at"`
+		FinishedAt *time.Time `json:"finished_at"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updated_at"`
+	}{
+		b.ID,
+		b.Branch,
+		b.Sha,
+		b.State,
+		b.LogURL,
+		b.StartedAt,
+		b.FinishedAt,
+		b.CreatedAt,
+		b.UpdatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild_SetStatus(t *testing.T) {
+	startedAt := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		build       Build
+		status      BuildStatus
+		wantChanged bool
+		wantBuild   Build
+	}{
+		{
+			name:        "test - new build",
+			build:       Build{},
+			status:      BuildStatus{ID: "42", State: BuildStatePending, LogURL: "http://ci/42"},
+			wantChanged: true,
+			wantBuild:   Build{ExternalID: "42", State: BuildStatePending, LogURL: "http://ci/42"},
+		},
+		{
+			name:        "test - build started keeps the external id and log url",
+			build:       Build{ExternalID: "42", State: BuildStatePending, LogURL: "http://ci/42"},
+			status:      BuildStatus{State: BuildStateRunning, StartedAt: &startedAt},
+			wantChanged: true,
+			wantBuild:   Build{ExternalID: "42", State: BuildStateRunning, LogURL: "http://ci/42", StartedAt: &startedAt},
+		},
+		{
+			name:        "test - same state",
+			build:       Build{ExternalID: "42", State: BuildStateRunning},
+			status:      BuildStatus{ID: "42", State: BuildStateRunning},
+			wantChanged: false,
+			wantBuild:   Build{ExternalID: "42", State: BuildStateRunning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := tt.build.SetStatus(&tt.status)
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, tt.wantBuild, tt.build)
+		})
+	}
+}
+
+func TestBuild_IsFinished(t *testing.T) {
+	tests := []struct {
+		state string
+		want  bool
+	}{
+		{BuildStatePending, false},
+		{BuildStateRunning, false},
+		{BuildStateSuccess, true},
+		{BuildStateFailure, true},
+		{BuildStateError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			b := &Build{State: tt.state}
+			assert.Equal(t, tt.want, b.IsFinished())
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//Changelog sections, in the order they are rendered
+const (
+	ChangelogBreakingChanges = "Breaking Changes"
+	ChangelogFeatures        = "Features"
+	ChangelogBugFixes        = "Bug Fixes"
+	ChangelogPerformance     = "Performance"
+	ChangelogDocumentation   = "Documentation"
+	ChangelogOtherChanges    = "Other Changes"
+)
+
+var changelogSectionsOrder = []string{
+	ChangelogBreakingChanges,
+	ChangelogFeatures,
+	ChangelogBugFixes,
+	ChangelogPerformance,
+	ChangelogDocumentation,
+	ChangelogOtherChanges,
+}
+
+var changelogLabels = map[string]string{
+	"breaking":        ChangelogBreakingChanges,
+	"breaking-change": ChangelogBreakingChanges,
+	"feature":         ChangelogFeatures,
+	"enhancement":     ChangelogFeatures,
+	"bug":             ChangelogBugFixes,
+	"bugfix":          ChangelogBugFixes,
+	"fix":             ChangelogBugFixes,
+	"performance":     ChangelogPerformance,
+	"documentation":   ChangelogDocumentation,
+	"docs":            ChangelogDocumentation,
+}
+
+var changelogTypes = map[string]string{
+	"feat": ChangelogFeatures,
+	"fix":  ChangelogBugFixes,
+	"perf": ChangelogPerformance,
+	"docs": ChangelogDocumentation,
+}
+
+//Merge commits created by Github ("Merge pull request #12 from ...") and squashed ones ("title (#12)")
+var pullRequestReference = regexp.MustCompile(`^Merge pull request #(\d+)|\(#(\d+)\)$`)
+
+//MergedPullRequest is a pull request merged between two refs and the commit which merged it.
+type MergedPullRequest struct {
+	Number int
+	Commit Commit
+}
+
+//ChangelogEntry is a single change of a changelog, a merged pull request or a commit pushed directly.
+type ChangelogEntry struct {
+	Title             string `json:"title"`
+	PullRequestNumber int    `json:"pull_request_number,omitempty"`
+	Sha               string `json:"sha"`
+	Author            string `json:"author"`
+}
+
+//ChangelogSection groups the changes of the same kind.
+type ChangelogSection struct {
+	Title   string           `json:"title"`
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+//Changelog represents the changes between two refs of a repository.
+type Changelog struct {
+	Version  string             `json:"version"`
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Sections []ChangelogSection `json:"sections"`
+}
+
+//GetPullRequestNumber returns the pull request referenced by a merge or squash commit message.
+func GetPullRequestNumber(message string) (int, bool) {
+	title := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+
+	matches := pullRequestReference.FindStringSubmatch(title)
+	if matches == nil {
+		return 0, false
+	}
+
+	number := matches[1]
+	if number == "" {
+		number = matches[2]
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+//SplitCommits separates the pull requests merged into the mainline from the commits pushed directly to it.
+//The commits brought by a merged pull request are not returned as direct commits, neither the merge commits.
+func SplitCommits(commits []Commit) ([]MergedPullRequest, []Commit) {
+	inRange := make(map[string]Commit)
+	isParent := make(map[string]bool)
+	for _, c := range commits {
+		inRange[c.Sha] = c
+		for _, p := range c.Parents {
+			isParent[p.Sha] = true
+		}
+	}
+
+	//The mainline goes from the heads of the range through the first parents
+	mainline := make(map[string]bool)
+	for _, c := range commits {
+		if isParent[c.Sha] {
+			continue
+		}
+		for current, ok := c, true; ok && !mainline[current.Sha]; {
+			mainline[current.Sha] = true
+			if len(current.Parents) == 0 {
+				break
+			}
+			current, ok = inRange[current.Parents[0].Sha]
+		}
+	}
+
+	//Commits reachable from the merged side of a pull request merge belong to the pull request
+	absorbed := make(map[string]bool)
+	var absorb func(sha string)
+	absorb = func(sha string) {
+		c, ok := inRange[sha]
+		if !ok || mainline[sha] || absorbed[sha] {
+			return
+		}
+		absorbed[sha] = true
+		for _, p := range c.Parents {
+			absorb(p.Sha)
+		}
+	}
+
+	merged := make([]MergedPullRequest, 0)
+	for _, c := range commits {
+		if !mainline[c.Sha] {
+			continue
+		}
+		if number, ok := GetPullRequestNumber(c.Commit.Message); ok {
+			merged = append(merged, MergedPullRequest{Number: number, Commit: c})
+			//Squashed pull requests and root commits have no merged parents
+			if len(c.Parents) > 1 {
+				for _, p := range c.Parents[1:] {
+					absorb(p.Sha)
+				}
+			}
+		}
+	}
+
+	direct := make([]Commit, 0)
+	for _, c := range commits {
+		if _, isPullRequest := GetPullRequestNumber(c.Commit.Message); isPullRequest && mainline[c.Sha] {
+			continue
+		}
+		if absorbed[c.Sha] || len(c.Parents) > 1 {
+			continue
+		}
+		direct = append(direct, c)
+	}
+
+	return merged, direct
+}
+
+//NewChangelog builds the changelog of a version grouping its changes by the pull requests labels or,
+//if none of them is known, by the Conventional Commits type of its title.
+//The pull requests details are optional, the merge commit message is used when they are missing.
+func NewChangelog(version string, from string, to string, merged []MergedPullRequest, direct []Commit, prs map[int]*PullRequest) *Changelog {
+	entries := make(map[string][]ChangelogEntry)
+
+	for _, m := range merged {
+		entry := ChangelogEntry{
+			Title:             pullRequestTitle(m.Commit.Commit.Message),
+			PullRequestNumber: m.Number,
+			Sha:               m.Commit.Sha,
+			Author:            m.Commit.Author.Login,
+		}
+
+		var labels []string
+		if pr, ok := prs[m.Number]; ok && pr != nil {
+			entry.Title = pr.Title
+			entry.Author = pr.User.Login
+			for _, l := range pr.Labels {
+				labels = append(labels, l.Name)
+			}
+		}
+
+		section := getChangelogSection(labels, entry.Title)
+		entries[section] = append(entries[section], entry)
+	}
+
+	for _, c := range direct {
+		entry := ChangelogEntry{
+			Title:  commitTitle(c.Commit.Message),
+			Sha:    c.Sha,
+			Author: c.Author.Login,
+		}
+
+		section := getChangelogSection(nil, c.Commit.Message)
+		entries[section] = append(entries[section], entry)
+	}
+
+	cl := Changelog{
+		Version:  version,
+		From:     from,
+		To:       to,
+		Sections: make([]ChangelogSection, 0),
+	}
+
+	for _, title := range changelogSectionsOrder {
+		if len(entries[title]) > 0 {
+			cl.Sections = append(cl.Sections, ChangelogSection{
+				Title:   title,
+				Entries: entries[title],
+			})
+		}
+	}
+
+	return &cl
+}
+
+//Markdown renders the changelog as a Markdown document.
+func (cl *Changelog) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## %s\n", cl.Version))
+	sb.WriteString(fmt.Sprintf("\nChanges from %s to %s\n", cl.From, cl.To))
+
+	if len(cl.Sections) == 0 {
+		sb.WriteString("\nNo changes.\n")
+	}
+
+	for _, section := range cl.Sections {
+		sb.WriteString(fmt.Sprintf("\n### %s\n\n", section.Title))
+		for _, e := range section.Entries {
+			ref := shortSha(e.Sha)
+			if e.PullRequestNumber != 0 {
+				ref = fmt.Sprintf("#%d", e.PullRequestNumber)
+			}
+			if e.Author != "" {
+				sb.WriteString(fmt.Sprintf("- %s (%s) @%s\n", e.Title, ref, e.Author))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s (%s)\n", e.Title, ref))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+//getChangelogSection classifies a change by its labels or, if none of them is known, by its message.
+func getChangelogSection(labels []string, message string) string {
+	for _, label := range labels {
+		if section, ok := changelogLabels[strings.ToLower(label)]; ok {
+			return section
+		}
+	}
+
+	cc, ok := ParseConventionalCommit(message)
+	if !ok {
+		return ChangelogOtherChanges
+	}
+
+	if cc.BreakingChange {
+		return ChangelogBreakingChanges
+	}
+
+	if section, ok := changelogTypes[cc.Type]; ok {
+		return section
+	}
+
+	return ChangelogOtherChanges
+}
+
+//pullRequestTitle extracts the pull request title from its merge commit message.
+//Github writes it in the body of merge commits and at the beginning of squashed ones.
+func pullRequestTitle(message string) string {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+
+	if strings.HasPrefix(lines[0], "Merge pull request") {
+		for _, line := range lines[1:] {
+			if strings.TrimSpace(line) != "" {
+				return strings.TrimSpace(line)
+			}
+		}
+		return strings.TrimSpace(lines[0])
+	}
+
+	title := strings.TrimSpace(lines[0])
+	if loc := pullRequestReference.FindStringIndex(title); loc != nil {
+		title = strings.TrimSpace(title[:loc[0]])
+	}
+
+	return title
+}
+
+func commitTitle(message string) string {
+	return strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+}
+
+func shortSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+)
+
+func newChangelogCommit(sha string, message string, parents ...string) Commit {
+	var c Commit
+	c.Sha = sha
+	c.Commit.Message = message
+	for _, p := range parents {
+		c.Parents = append(c.Parents, struct {
+			Sha string `json:"sha"`
+		}{p})
+	}
+	return c
+}
+
+func TestGetPullRequestNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    int
+		wantOk  bool
+	}{
+		{name: "merge commit", message: "Merge pull request #42 from herbal828/feature/changelog\n\nfeat: changelog", want: 42, wantOk: true},
+		{name: "squash commit", message: "feat: changelog (#43)", want: 43, wantOk: true},
+		{name: "regular commit", message: "fix: issue #44 in the middle", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetPullRequestNumber(tt.message)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("GetPullRequestNumber() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSplitCommits(t *testing.T) {
+	//base <- a (direct) <- m1 (merges b1 <- b2) <- s (squash) <- c (direct)
+	commits := []Commit{
+		newChangelogCommit("a", "fix: direct fix", "base"),
+		newChangelogCommit("b1", "feat: first", "a"),
+		newChangelogCommit("b2", "feat: second", "b1"),
+		newChangelogCommit("m1", "Merge pull request #1 from herbal828/feature/x", "a", "b2"),
+		newChangelogCommit("s", "feat: squashed (#2)", "m1"),
+		newChangelogCommit("c", "docs: readme", "s"),
+	}
+
+	merged, direct := SplitCommits(commits)
+
+	if len(merged) != 2 || merged[0].Number != 1 || merged[1].Number != 2 {
+		t.Errorf("SplitCommits() merged = %v, want pull requests 1 and 2", merged)
+	}
+
+	if len(direct) != 2 || direct[0].Sha != "a" || direct[1].Sha != "c" {
+		t.Errorf("SplitCommits() direct = %v, want commits a and c", direct)
+	}
+}
+
+func TestSplitCommits_RootPullRequestCommit(t *testing.T) {
+	commits := []Commit{
+		newChangelogCommit("root", "Initial commit (#1)"),
+		newChangelogCommit("a", "fix: direct fix", "root"),
+	}
+
+	merged, direct := SplitCommits(commits)
+
+	if len(merged) != 1 || merged[0].Number != 1 {
+		t.Errorf("SplitCommits() merged = %v, want pull request 1", merged)
+	}
+
+	if len(direct) != 1 || direct[0].Sha != "a" {
+		t.Errorf("SplitCommits() direct = %v, want commit a", direct)
+	}
+}
+
+func TestChangelog_Markdown(t *testing.T) {
+	merged := []MergedPullRequest{
+		{Number: 1, Commit: newChangelogCommit("m1", "Merge pull request #1 from herbal828/feature/x\n\nfeat: new endpoint")},
+		{Number: 3, Commit: newChangelogCommit("m3", "Merge pull request #3 from herbal828/fix/y\n\nfix: broken link")},
+		{Number: 2, Commit: newChangelogCommit("s", "feat: squashed (#2)")},
+	}
+	direct := []Commit{newChangelogCommit("a1b2c3d4e5", "fix: direct fix")}
+
+	pr := PullRequest{Title: "New endpoint"}
+	pr.User.Login = "herbal828"
+	pr.Labels = append(pr.Labels, struct {
+		Name string `json:"name"`
+	}{"enhancement"})
+
+	cl := NewChangelog("1.1.0", "v1.0.0", "release/1.1.0", merged, direct, map[int]*PullRequest{1: &pr})
+
+	want := "## 1.1.0\n\nChanges from v1.0.0 to release/1.1.0\n\n" +
+		"### Features\n\n- New endpoint (#1) @herbal828\n- feat: squashed (#2)\n\n" +
+		"### Bug Fixes\n\n- fix: broken link (#3)\n- fix: direct fix (a1b2c3d)\n"
+
+	if got := cl.Markdown(); got != want {
+		t.Errorf("Changelog.Markdown() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+//BumpNone means the commits do not require a new version
+const BumpNone = "none"
+
+var conventionalCommitHeader = regexp.MustCompile(`^(\w+)(\(([^)]*)\))?(!)?: (.+)$`)
+
+//ConventionalCommit represents a commit message following the Conventional Commits specification.
+//https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	Type           string
+	Scope          string
+	Description    string
+	BreakingChange bool
+}
+
+//ParseConventionalCommit parses a commit message.
+//The second value is false when the message does not follow the Conventional Commits specification.
+func ParseConventionalCommit(message string) (*ConventionalCommit, bool) {
+	lines := strings.SplitN(strings.TrimSpace(message), "\n", 2)
+
+	matches := conventionalCommitHeader.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if matches == nil {
+		return nil, false
+	}
+
+	cc := ConventionalCommit{
+		Type:           strings.ToLower(matches[1]),
+		Scope:          matches[3],
+		Description:    matches[5],
+		BreakingChange: matches[4] == "!",
+	}
+
+	//The body or footers can also declare a breaking change
+	if len(lines) > 1 && (strings.Contains(lines[1], "BREAKING CHANGE:") || strings.Contains(lines[1], "BREAKING-CHANGE:")) {
+		cc.BreakingChange = true
+	}
+
+	return &cc, true
+}
+
+//GetBump returns the semantic version bump required by the commit.
+func (cc *ConventionalCommit) GetBump() string {
+	switch {
+	case cc.BreakingChange:
+		return BumpMajor
+	case cc.Type == "feat":
+		return BumpMinor
+	case cc.Type == "fix":
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
+
+//CommitReason explains the version bump required by a single commit.
+type CommitReason struct {
+	Sha     string `json:"sha"`
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Bump    string `json:"bump"`
+}
+
+//NextVersion represents the next semantic version of a repository branch and the reasons behind it.
+type NextVersion struct {
+	Branch    string         `json:"branch"`
+	BaseTag   string         `json:"base_tag"`
+	Current   string         `json:"current"`
+	Next      string         `json:"next"`
+	Bump      string         `json:"bump"`
+	Reasoning []CommitReason `json:"reasoning"`
+}
+
+//CalculateNextVersion classifies the commits and bumps the current version with the greatest change found.
+//Commits not following the Conventional Commits specification do not require a new version.
+func CalculateNextVersion(current Version, commits []Commit) *NextVersion {
+	weight := map[string]int{BumpNone: 0, BumpPatch: 1, BumpMinor: 2, BumpMajor: 3}
+
+	nv := NextVersion{
+		Current:   current.String(),
+		Next:      current.String(),
+		Bump:      BumpNone,
+		Reasoning: make([]CommitReason, 0),
+	}
+
+	for _, c := range commits {
+		reason := CommitReason{
+			Sha:     c.Sha,
+			Message: strings.SplitN(c.Commit.Message, "\n", 2)[0],
+			Bump:    BumpNone,
+		}
+
+		if cc, ok := ParseConventionalCommit(c.Commit.Message); ok {
+			reason.Type = cc.Type
+			reason.Bump = cc.GetBump()
+		}
+
+		if weight[reason.Bump] > weight[nv.Bump] {
+			nv.Bump = reason.Bump
+		}
+
+		nv.Reasoning = append(nv.Reasoning, reason)
+	}
+
+	if nv.Bump != BumpNone {
+		next, _ := current.Bump(nv.Bump)
+		nv.Next = next.String()
+	}
+
+	return &nv
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    *ConventionalCommit
+		wantOk  bool
+	}{
+		{
+			name:    "feature with scope",
+			message: "feat(api): add releases endpoint",
+			want:    &ConventionalCommit{Type: "feat", Scope: "api", Description: "add releases endpoint"},
+			wantOk:  true,
+		},
+		{
+			name:    "breaking change mark",
+			message: "refactor!: drop legacy routes",
+			want:    &ConventionalCommit{Type: "refactor", Description: "drop legacy routes", BreakingChange: true},
+			wantOk:  true,
+		},
+		{
+			name:    "breaking change footer",
+			message: "fix: rename payload field\n\nBREAKING CHANGE: owner is now required",
+			want:    &ConventionalCommit{Type: "fix", Description: "rename payload field", BreakingChange: true},
+			wantOk:  true,
+		},
+		{
+			name:    "merge commit",
+			message: "Merge pull request #12 from herbal828/feature/releases",
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseConventionalCommit(tt.message)
+			if ok != tt.wantOk {
+				t.Errorf("ParseConventionalCommit() ok = %v, want %v", ok, tt.wantOk)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConventionalCommit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateNextVersion(t *testing.T) {
+	newCommit := func(sha string, message string) Commit {
+		var c Commit
+		c.Sha = sha
+		c.Commit.Message = message
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		commits  []Commit
+		wantNext string
+		wantBump string
+	}{
+		{
+			name:     "no releasable commits",
+			commits:  []Commit{newCommit("a1", "chore: update deps"), newCommit("b2", "Merge branch develop")},
+			wantNext: "1.2.3",
+			wantBump: BumpNone,
+		},
+		{
+			name:     "fixes and features",
+			commits:  []Commit{newCommit("a1", "fix: npe"), newCommit("b2", "feat: new endpoint"), newCommit("c3", "fix: typo")},
+			wantNext: "1.3.0",
+			wantBump: BumpMinor,
+		},
+		{
+			name:     "breaking change",
+			commits:  []Commit{newCommit("a1", "feat!: new payload")},
+			wantNext: "2.0.0",
+			wantBump: BumpMajor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateNextVersion(Version{1, 2, 3}, tt.commits)
+			if got.Next != tt.wantNext || got.Bump != tt.wantBump {
+				t.Errorf("CalculateNextVersion() = %s (%s), want %s (%s)", got.Next, got.Bump, tt.wantNext, tt.wantBump)
+			}
+			if len(got.Reasoning) != len(tt.commits) {
+				t.Errorf("CalculateNextVersion() reasoning has %d commits, want %d", len(got.Reasoning), len(tt.commits))
+			}
+		})
+	}
+}
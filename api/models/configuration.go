@@ -1,6 +1,8 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,7 +20,12 @@ type PostRequestPayload struct {
 
 	CodeCoverage struct {
 		PullRequestThreshold *float64 `json:"pull_request_threshold"`
+		MaxDecrease          *float64 `json:"max_decrease"`
 	} `json:"code_coverage"`
+
+	Deployment struct {
+		Environments []EnvironmentPayload `json:"environments"`
+	} `json:"deployment"`
 }
 
 //PutRequestPayload represents the payload received in the PUT request.
@@ -30,7 +37,12 @@ type PutRequestPayload struct {
 
 	CodeCoverage struct {
 		PullRequestThreshold *float64 `json:"pull_request_threshold"`
+		MaxDecrease          *float64 `json:"max_decrease"`
 	} `json:"code_coverage"`
+
+	Deployment struct {
+		Environments []EnvironmentPayload `json:"environments"`
+	} `json:"deployment"`
 }
 
 //Configuration represents the only business object of this API.
@@ -42,6 +54,12 @@ type Configuration struct {
 	RepositoryStatusChecks           []RequireStatusCheck
 	WorkflowType                     *string
 	CodeCoveragePullRequestThreshold *float64
+	CodeCoverageMaxDecrease          *float64
+	WebhookID                        *int64
+	Environments                     []Environment
+
+	//Version is increased on every change, it identifies the revision of the configuration the clients saw
+	Version uint64 `gorm:"not null;default:1"`
 
 	//GORM date attributes
 	CreatedAt time.Time
@@ -56,15 +74,26 @@ type RequireStatusCheck struct {
 	ConfigurationID *string
 }
 
+//GetConfigurationID returns the ID of the configuration of a repository.
+//It is composed by the repository owner and name, as the repositories of different owners can share the name.
+func GetConfigurationID(owner string, name string) string {
+	return owner + "/" + name
+}
+
 //NewConfiguration converts a PostRequestPayload into a Configuration.
 func NewConfiguration(r *PostRequestPayload) *Configuration {
 	var c Configuration
 
 	c.ID = r.Repository.Name
+	if r.Repository.Owner != nil && r.Repository.Name != nil {
+		id := GetConfigurationID(*r.Repository.Owner, *r.Repository.Name)
+		c.ID = &id
+	}
 	c.RepositoryName = r.Repository.Name
 	c.RepositoryOwner = r.Repository.Owner
 	c.WorkflowType = r.Workflow.Type
 	c.CodeCoveragePullRequestThreshold = r.CodeCoverage.PullRequestThreshold
+	c.CodeCoverageMaxDecrease = r.CodeCoverage.MaxDecrease
 
 	reqChecks := make([]RequireStatusCheck, 0)
 	for _, rq := range r.Repository.RequireStatusChecks {
@@ -74,16 +103,40 @@ func NewConfiguration(r *PostRequestPayload) *Configuration {
 	}
 
 	c.RepositoryStatusChecks = reqChecks
+	c.Environments = NewEnvironments(r.Deployment.Environments)
+	c.Version = 1
 
 	return &c
 }
 
+//GetETag returns the entity tag of the current version of the configuration.
+func (c *Configuration) GetETag() string {
+	return `"` + strconv.FormatUint(c.Version, 10) + `"`
+}
+
+//MatchesETag reports if an If-Match header value matches the current version of the configuration.
+//The header could have a list of entity tags or '*', which matches any version.
+func (c *Configuration) MatchesETag(ifMatch string) bool {
+	etag := c.GetETag()
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
 //UpdateConfiguration updates a Configuration based on a PutRequestPayload.
 func (c *Configuration) UpdateConfiguration(r *PutRequestPayload) {
 	if r.CodeCoverage.PullRequestThreshold != nil {
 		c.CodeCoveragePullRequestThreshold = r.CodeCoverage.PullRequestThreshold
 	}
 
+	if r.CodeCoverage.MaxDecrease != nil {
+		c.CodeCoverageMaxDecrease = r.CodeCoverage.MaxDecrease
+	}
+
 	if r.Repository.RequireStatusChecks != nil {
 		reqChecks := make([]RequireStatusCheck, 0)
 		for _, rq := range r.Repository.RequireStatusChecks {
@@ -93,6 +146,80 @@ func (c *Configuration) UpdateConfiguration(r *PutRequestPayload) {
 		}
 		c.RepositoryStatusChecks = reqChecks
 	}
+
+	if r.Deployment.Environments != nil {
+		c.Environments = NewEnvironments(r.Deployment.Environments)
+	}
+}
+
+//UpsertConfiguration applies to an existing Configuration the differences of a PostRequestPayload.
+//It returns true when the workflow type or the required status checks changed, so the workflow has to be set again.
+func (c *Configuration) UpsertConfiguration(r *PostRequestPayload) bool {
+	workflowChanged := false
+
+	if r.Workflow.Type != nil && (c.WorkflowType == nil || *c.WorkflowType != *r.Workflow.Type) {
+		c.WorkflowType = r.Workflow.Type
+		workflowChanged = true
+	}
+
+	if r.CodeCoverage.PullRequestThreshold != nil {
+		c.CodeCoveragePullRequestThreshold = r.CodeCoverage.PullRequestThreshold
+	}
+
+	if r.CodeCoverage.MaxDecrease != nil {
+		c.CodeCoverageMaxDecrease = r.CodeCoverage.MaxDecrease
+	}
+
+	if r.Repository.RequireStatusChecks != nil && !EqualChecks(c.GetRequiredStatusCheck(), r.Repository.RequireStatusChecks) {
+		reqChecks := make([]RequireStatusCheck, 0)
+		for _, rq := range r.Repository.RequireStatusChecks {
+			reqChecks = append(reqChecks, RequireStatusCheck{
+				Check: rq,
+			})
+		}
+		c.RepositoryStatusChecks = reqChecks
+		workflowChanged = true
+	}
+
+	if r.Deployment.Environments != nil {
+		c.Environments = NewEnvironments(r.Deployment.Environments)
+	}
+
+	return workflowChanged
+}
+
+//EqualChecks reports if two lists of required status checks have the same checks in the same order.
+//A nil list is equal to an empty one.
+func EqualChecks(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//ToPutRequestPayload converts the Configuration into the PutRequestPayload which would leave it unchanged.
+//It is the document the partial updates are applied to.
+func (c *Configuration) ToPutRequestPayload() *PutRequestPayload {
+	var r PutRequestPayload
+
+	r.Repository.RequireStatusChecks = c.GetRequiredStatusCheck()
+	if r.Repository.RequireStatusChecks == nil {
+		r.Repository.RequireStatusChecks = make([]string, 0)
+	}
+	r.CodeCoverage.PullRequestThreshold = c.CodeCoveragePullRequestThreshold
+	r.CodeCoverage.MaxDecrease = c.CodeCoverageMaxDecrease
+
+	r.Deployment.Environments = make([]EnvironmentPayload, 0)
+	for _, env := range c.GetEnvironments() {
+		r.Deployment.Environments = append(r.Deployment.Environments, env.ToPayload())
+	}
+
+	return &r
 }
 
 //GetRequiredStatusCheck maps the RepositoryStatusChecks field in the Configuration struct into a string slice.
@@ -105,8 +232,13 @@ func (c *Configuration) GetRequiredStatusCheck() []string {
 }
 
 //Marshall converts the Configuration struct into a readable JSON interface.
+//Fields which are not set are rendered with their zero value.
 func (c *Configuration) Marshall() interface{} {
 	rsc := c.GetRequiredStatusCheck()
+	envs := make([]interface{}, 0)
+	for _, env := range c.GetEnvironments() {
+		envs = append(envs, env.Marshall())
+	}
 	return &struct {
 		ID         string `json:"id"`
 		Repository struct {
@@ -115,31 +247,56 @@ func (c *Configuration) Marshall() interface{} {
 			RequiredStatusCheck []string `json:"required_status_check"`
 		} `json:"repository"`
 		CodeCoverage struct {
-			PullRequestThreshold float64 `json:"pull_request_threshold"`
+			PullRequestThreshold float64  `json:"pull_request_threshold"`
+			MaxDecrease          *float64 `json:"max_decrease"`
 		} `json:"code_coverage"`
 		Workflow struct {
 			Type string `json:"type"`
 		} `json:"workflow"`
+		Deployment struct {
+			Environments []interface{} `json:"environments"`
+		} `json:"deployment"`
 	}{
-		*c.ID,
+		stringValue(c.ID),
 		struct {
 			Name                string   `json:"name"`
 			Owner               string   `json:"owner"`
 			RequiredStatusCheck []string `json:"required_status_check"`
 		}{
-			*c.RepositoryName,
-			*c.RepositoryOwner,
+			stringValue(c.RepositoryName),
+			stringValue(c.RepositoryOwner),
 			rsc,
 		},
 		struct {
-			PullRequestThreshold float64 `json:"pull_request_threshold"`
+			PullRequestThreshold float64  `json:"pull_request_threshold"`
+			MaxDecrease          *float64 `json:"max_decrease"`
 		}{
-			*c.CodeCoveragePullRequestThreshold,
+			float64Value(c.CodeCoveragePullRequestThreshold),
+			c.CodeCoverageMaxDecrease,
 		},
 		struct {
 			Type string `json:"type"`
 		}{
-			*c.WorkflowType,
+			stringValue(c.WorkflowType),
 		},
+		struct {
+			Environments []interface{} `json:"environments"`
+		}{
+			envs,
+		},
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func float64Value(f *float64) float64 {
+	if f == nil {
+		return 0
 	}
+	return *f
 }
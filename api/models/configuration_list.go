@@ -0,0 +1,195 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Limits of the configuration list pages
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+//ErrInvalidListQuery is returned when the query params of a list request can not be parsed.
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+//ListCursor points to the last element of a page, the next page starts right after it.
+type ListCursor struct {
+	Time time.Time
+	ID   string
+}
+
+//ConfigurationListQuery represents the filters, the order and the page of a configuration list request.
+type ConfigurationListQuery struct {
+	Owner        string
+	WorkflowType string
+	MinThreshold *float64
+	MaxThreshold *float64
+	SortBy       string
+	Desc         bool
+	Limit        int
+	Cursor       *ListCursor
+}
+
+//ParseConfigurationListQuery builds a ConfigurationListQuery from the request query params:
+//owner, workflow_type, min_threshold, max_threshold, sort (created_at or updated_at, '-' prefix for
+//descending order), limit and cursor.
+func ParseConfigurationListQuery(query func(string) string) (*ConfigurationListQuery, error) {
+	q := ConfigurationListQuery{
+		Owner:        query("owner"),
+		WorkflowType: query("workflow_type"),
+		SortBy:       "created_at",
+		Limit:        DefaultListLimit,
+	}
+
+	for param, threshold := range map[string]**float64{"min_threshold": &q.MinThreshold, "max_threshold": &q.MaxThreshold} {
+		if v := query(param); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, ErrInvalidListQuery
+			}
+			*threshold = &f
+		}
+	}
+
+	if sort := query("sort"); sort != "" {
+		q.Desc = strings.HasPrefix(sort, "-")
+		q.SortBy = strings.TrimPrefix(sort, "-")
+		if q.SortBy != "created_at" && q.SortBy != "updated_at" {
+			return nil, ErrInvalidListQuery
+		}
+	}
+
+	if limit := query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l <= 0 {
+			return nil, ErrInvalidListQuery
+		}
+		if l > MaxListLimit {
+			l = MaxListLimit
+		}
+		q.Limit = l
+	}
+
+	if cursor := query("cursor"); cursor != "" {
+		c, err := DecodeListCursor(cursor)
+		if err != nil {
+			return nil, ErrInvalidListQuery
+		}
+		q.Cursor = c
+	}
+
+	return &q, nil
+}
+
+//Where returns the SQL condition and its arguments which select the configurations of the page.
+//The first element is empty when there is no condition.
+func (q *ConfigurationListQuery) Where() []interface{} {
+	conds := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if q.Owner != "" {
+		conds = append(conds, "repository_owner = ?")
+		args = append(args, q.Owner)
+	}
+	if q.WorkflowType != "" {
+		conds = append(conds, "workflow_type = ?")
+		args = append(args, q.WorkflowType)
+	}
+	if q.MinThreshold != nil {
+		conds = append(conds, "code_coverage_pull_request_threshold >= ?")
+		args = append(args, *q.MinThreshold)
+	}
+	if q.MaxThreshold != nil {
+		conds = append(conds, "code_coverage_pull_request_threshold <= ?")
+		args = append(args, *q.MaxThreshold)
+	}
+
+	//The id breaks the ties of the sort column, so the cursor is never ambiguous
+	if q.Cursor != nil {
+		op := ">"
+		if q.Desc {
+			op = "<"
+		}
+		conds = append(conds, fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", q.SortBy, op, q.SortBy, op))
+		args = append(args, q.Cursor.Time, q.Cursor.Time, q.Cursor.ID)
+	}
+
+	return append([]interface{}{strings.Join(conds, " AND ")}, args...)
+}
+
+//Order returns the SQL order of the configurations.
+func (q *ConfigurationListQuery) Order() string {
+	if q.Desc {
+		return fmt.Sprintf("%s desc, id desc", q.SortBy)
+	}
+	return fmt.Sprintf("%s asc, id asc", q.SortBy)
+}
+
+//NextCursor returns the cursor of the page which follows the given configuration.
+func (q *ConfigurationListQuery) NextCursor(last *Configuration) string {
+	t := last.CreatedAt
+	if q.SortBy == "updated_at" {
+		t = last.UpdatedAt
+	}
+	return EncodeListCursor(&ListCursor{Time: t, ID: *last.ID})
+}
+
+//Values returns the query params of the page which starts at the given cursor.
+func (q *ConfigurationListQuery) Values(cursor string) url.Values {
+	v := url.Values{}
+	if q.Owner != "" {
+		v.Set("owner", q.Owner)
+	}
+	if q.WorkflowType != "" {
+		v.Set("workflow_type", q.WorkflowType)
+	}
+	if q.MinThreshold != nil {
+		v.Set("min_threshold", strconv.FormatFloat(*q.MinThreshold, 'f', -1, 64))
+	}
+	if q.MaxThreshold != nil {
+		v.Set("max_threshold", strconv.FormatFloat(*q.MaxThreshold, 'f', -1, 64))
+	}
+	sort := q.SortBy
+	if q.Desc {
+		sort = "-" + sort
+	}
+	v.Set("sort", sort)
+	v.Set("limit", strconv.Itoa(q.Limit))
+	if cursor != "" {
+		v.Set("cursor", cursor)
+	}
+	return v
+}
+
+//EncodeListCursor converts a cursor into an opaque string.
+func EncodeListCursor(c *ListCursor) string {
+	raw := fmt.Sprintf("%s|%s", c.Time.UTC().Format(time.RFC3339Nano), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+//DecodeListCursor converts an opaque string into a cursor.
+func DecodeListCursor(s string) (*ListCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errors.New("invalid cursor")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListCursor{Time: t, ID: parts[1]}, nil
+}
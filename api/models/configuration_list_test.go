@@ -0,0 +1,85 @@
+package models
+
+import (
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigurationListQuery(t *testing.T) {
+	cursor := EncodeListCursor(&ListCursor{Time: time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC), ID: "ci_cd-api"})
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		wantWhere []interface{}
+		wantOrder string
+		wantLimit int
+		wantErr   bool
+	}{
+		{
+			name:      "test - defaults",
+			params:    map[string]string{},
+			wantWhere: []interface{}{""},
+			wantOrder: "created_at asc, id asc",
+			wantLimit: DefaultListLimit,
+		},
+		{
+			name:      "test - filters and descending order",
+			params:    map[string]string{"owner": "herbal828", "min_threshold": "70.5", "sort": "-updated_at", "limit": "500"},
+			wantWhere: []interface{}{"repository_owner = ? AND code_coverage_pull_request_threshold >= ?", "herbal828", 70.5},
+			wantOrder: "updated_at desc, id desc",
+			wantLimit: MaxListLimit,
+		},
+		{
+			name:   "test - cursor",
+			params: map[string]string{"cursor": cursor, "workflow_type": "gitflow"},
+			wantWhere: []interface{}{
+				"workflow_type = ? AND (created_at > ? OR (created_at = ? AND id > ?))",
+				"gitflow",
+				time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC),
+				time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC),
+				"ci_cd-api",
+			},
+			wantOrder: "created_at asc, id asc",
+			wantLimit: DefaultListLimit,
+		},
+		{
+			name:    "test - invalid sort",
+			params:  map[string]string{"sort": "name"},
+			wantErr: true,
+		},
+		{
+			name:    "test - invalid cursor",
+			params:  map[string]string{"cursor": "not-a-cursor"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseConfigurationListQuery(func(key string) string { return tt.params[key] })
+			if tt.wantErr {
+				assert.Equal(t, ErrInvalidListQuery, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantWhere, q.Where())
+			assert.Equal(t, tt.wantOrder, q.Order())
+			assert.Equal(t, tt.wantLimit, q.Limit)
+		})
+	}
+}
+
+func TestConfigurationListQuery_NextCursor(t *testing.T) {
+	updatedAt := time.Date(2020, 3, 2, 8, 30, 0, 0, time.UTC)
+	q := &ConfigurationListQuery{SortBy: "updated_at", Desc: true, Limit: 10}
+
+	next := q.NextCursor(&Configuration{ID: utils.Stringify("ci_cd-api"), UpdatedAt: updatedAt})
+	cursor, err := DecodeListCursor(next)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ListCursor{Time: updatedAt, ID: "ci_cd-api"}, cursor)
+	assert.Equal(t, "cursor="+next+"&limit=10&sort=-updated_at", q.Values(next).Encode())
+}
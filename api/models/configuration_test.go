@@ -0,0 +1,196 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfiguration_ID(t *testing.T) {
+	tests := []struct {
+		name   string
+		owner  *string
+		repo   *string
+		wantID *string
+	}{
+		{
+			name:   "test - id composed by owner and name",
+			owner:  utils.Stringify("acme"),
+			repo:   utils.Stringify("api"),
+			wantID: utils.Stringify("acme/api"),
+		},
+		{
+			name:   "test - same name of another owner does not collide",
+			owner:  utils.Stringify("other-org"),
+			repo:   utils.Stringify("api"),
+			wantID: utils.Stringify("other-org/api"),
+		},
+		{
+			name:   "test - without owner",
+			repo:   utils.Stringify("api"),
+			wantID: utils.Stringify("api"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r PostRequestPayload
+			r.Repository.Owner = tt.owner
+			r.Repository.Name = tt.repo
+
+			assert.Equal(t, tt.wantID, NewConfiguration(&r).ID)
+		})
+	}
+}
+
+func TestConfiguration_UpsertConfiguration(t *testing.T) {
+	threshold := 80.0
+
+	tests := []struct {
+		name                string
+		workflowType        *string
+		checks              []string
+		threshold           *float64
+		wantWorkflowChanged bool
+		wantWorkflowType    string
+		wantChecks          []string
+		wantThreshold       float64
+	}{
+		{
+			name:                "test - same payload",
+			workflowType:        utils.Stringify("gitflow"),
+			checks:              []string{"ci"},
+			wantWorkflowChanged: false,
+			wantWorkflowType:    "gitflow",
+			wantChecks:          []string{"ci"},
+			wantThreshold:       60,
+		},
+		{
+			name:                "test - new threshold does not change the workflow",
+			threshold:           &threshold,
+			wantWorkflowChanged: false,
+			wantWorkflowType:    "gitflow",
+			wantChecks:          []string{"ci"},
+			wantThreshold:       80,
+		},
+		{
+			name:                "test - new workflow type",
+			workflowType:        utils.Stringify("trunk_based"),
+			wantWorkflowChanged: true,
+			wantWorkflowType:    "trunk_based",
+			wantChecks:          []string{"ci"},
+			wantThreshold:       60,
+		},
+		{
+			name:                "test - new required status checks",
+			checks:              []string{"ci", "coverage"},
+			wantWorkflowChanged: true,
+			wantWorkflowType:    "gitflow",
+			wantChecks:          []string{"ci", "coverage"},
+			wantThreshold:       60,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldThreshold := 60.0
+			c := Configuration{
+				WorkflowType:                     utils.Stringify("gitflow"),
+				RepositoryStatusChecks:           []RequireStatusCheck{{Check: "ci"}},
+				CodeCoveragePullRequestThreshold: &oldThreshold,
+			}
+
+			var r PostRequestPayload
+			r.Workflow.Type = tt.workflowType
+			r.Repository.RequireStatusChecks = tt.checks
+			r.CodeCoverage.PullRequestThreshold = tt.threshold
+
+			assert.Equal(t, tt.wantWorkflowChanged, c.UpsertConfiguration(&r))
+			assert.Equal(t, tt.wantWorkflowType, *c.WorkflowType)
+			assert.Equal(t, tt.wantChecks, c.GetRequiredStatusCheck())
+			assert.Equal(t, tt.wantThreshold, *c.CodeCoveragePullRequestThreshold)
+		})
+	}
+}
+
+func TestConfiguration_MatchesETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    bool
+	}{
+		{
+			name:    "test - current version",
+			ifMatch: `"3"`,
+			want:    true,
+		},
+		{
+			name:    "test - weak tag of the current version",
+			ifMatch: `W/"3"`,
+			want:    true,
+		},
+		{
+			name:    "test - list with the current version",
+			ifMatch: `"2", "3"`,
+			want:    true,
+		},
+		{
+			name:    "test - any version",
+			ifMatch: "*",
+			want:    true,
+		},
+		{
+			name:    "test - previous version",
+			ifMatch: `"2"`,
+			want:    false,
+		},
+		{
+			name:    "test - unquoted version",
+			ifMatch: "3",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Configuration{Version: 3}
+			assert.Equal(t, `"3"`, c.GetETag())
+			assert.Equal(t, tt.want, c.MatchesETag(tt.ifMatch))
+		})
+	}
+}
+
+func TestConfiguration_ToPutRequestPayload(t *testing.T) {
+	threshold := 80.0
+
+	var env EnvironmentPayload
+	env.Name = "production"
+	env.Ref = "master"
+	env.Approvers = []string{"octocat"}
+	env.Target.Type = EnvironmentTargetWebhook
+	env.Target.URL = "http://deployer/hooks"
+
+	var r PutRequestPayload
+	r.Repository.RequireStatusChecks = []string{"ci", "lint"}
+	r.CodeCoverage.PullRequestThreshold = &threshold
+	r.Deployment.Environments = []EnvironmentPayload{env}
+
+	var c Configuration
+	c.UpdateConfiguration(&r)
+
+	assert.Equal(t, &r, c.ToPutRequestPayload())
+	assert.Equal(t, []string{}, (&Configuration{}).ToPutRequestPayload().Repository.RequireStatusChecks)
+}
+
+func TestConfiguration_Marshall_NilFields(t *testing.T) {
+	c := Configuration{
+		ID: utils.Stringify("acme/api"),
+	}
+
+	var got []byte
+	assert.NotPanics(t, func() {
+		got = utils.GetBytes(c.Marshall())
+	})
+	assert.Contains(t, string(got), `"id":"acme/api"`)
+	assert.Contains(t, string(got), `"repository":{"name":"","owner":""`)
+	assert.Contains(t, string(got), `"pull_request_threshold":0`)
+	assert.Contains(t, string(got), `"workflow":{"type":""}`)
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+//CoverageStatusContext is the commit status context reported by the coverage checks.
+//It can be listed among the repository required status checks.
+const CoverageStatusContext = "coverage"
+
+//CoverageReport represents the coverage of a commit reported by Codecov or any other coverage tool.
+//The base branch is the one the pull request is merged into, its latest coverage is used to detect regressions.
+type CoverageReport struct {
+	Sha               string   `json:"sha"`
+	Branch            string   `json:"branch"`
+	BaseBranch        string   `json:"base_branch"`
+	PullRequestNumber *int     `json:"pull_request_number"`
+	ProjectCoverage   *float64 `json:"project_coverage"`
+	PatchCoverage     *float64 `json:"patch_coverage"`
+	TargetURL         string   `json:"target_url"`
+	Source            string   `json:"-"`
+}
+
+//CoverageEvaluation is the result of comparing a coverage report with the repository threshold
+//and with the coverage of the base branch.
+type CoverageEvaluation struct {
+	Sha               string   `json:"sha"`
+	PullRequestNumber *int     `json:"pull_request_number"`
+	ProjectCoverage   float64  `json:"project_coverage"`
+	PatchCoverage     *float64 `json:"patch_coverage"`
+	Threshold         float64  `json:"threshold"`
+	BaseBranch        string   `json:"base_branch,omitempty"`
+	BaseCoverage      *float64 `json:"base_coverage"`
+	MaxDecrease       *float64 `json:"max_decrease"`
+	State             string   `json:"state"`
+	Description       string   `json:"description"`
+}
+
+//Evaluate compares the project and patch coverage with the pull request threshold.
+//Both of them must reach the threshold, the patch coverage is only checked when it is reported.
+//When the max decrease and the base branch coverage are known, the project coverage can not drop
+//more than the max decrease from the base coverage.
+func (r *CoverageReport) Evaluate(threshold *float64, maxDecrease *float64, baseCoverage *float64) *CoverageEvaluation {
+	ev := CoverageEvaluation{
+		Sha:               r.Sha,
+		PullRequestNumber: r.PullRequestNumber,
+		PatchCoverage:     r.PatchCoverage,
+		BaseBranch:        r.BaseBranch,
+		BaseCoverage:      baseCoverage,
+		MaxDecrease:       maxDecrease,
+		State:             "success",
+	}
+
+	if threshold != nil {
+		ev.Threshold = *threshold
+	}
+
+	if r.ProjectCoverage != nil {
+		ev.ProjectCoverage = *r.ProjectCoverage
+	}
+
+	var failures []string
+	if ev.ProjectCoverage < ev.Threshold {
+		failures = append(failures, fmt.Sprintf("project coverage %.2f%% is below %.2f%%", ev.ProjectCoverage, ev.Threshold))
+	}
+	if r.PatchCoverage != nil && *r.PatchCoverage < ev.Threshold {
+		failures = append(failures, fmt.Sprintf("patch coverage %.2f%% is below %.2f%%", *r.PatchCoverage, ev.Threshold))
+	}
+	if maxDecrease != nil && baseCoverage != nil && *baseCoverage-ev.ProjectCoverage > *maxDecrease {
+		failures = append(failures, fmt.Sprintf("coverage dropped %.2f%% from %s (max %.2f%%)", *baseCoverage-ev.ProjectCoverage, r.BaseBranch, *maxDecrease))
+	}
+
+	if len(failures) > 0 {
+		ev.State = "failure"
+		ev.Description = strings.Join(failures, ", ")
+	} else if r.PatchCoverage != nil {
+		ev.Description = fmt.Sprintf("project %.2f%%, patch %.2f%% (threshold %.2f%%)", ev.ProjectCoverage, *r.PatchCoverage, ev.Threshold)
+	} else {
+		ev.Description = fmt.Sprintf("project %.2f%% (threshold %.2f%%)", ev.ProjectCoverage, ev.Threshold)
+	}
+
+	return &ev
+}
+
+//CodecovWebhookPayload represents the fields this API uses from the Codecov webhook notifications.
+type CodecovWebhookPayload struct {
+	Repo struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Username string `json:"username"`
+		} `json:"owner"`
+	} `json:"repo"`
+	Head struct {
+		URL      string `json:"url"`
+		Commitid string `json:"commitid"`
+		Branch   string `json:"branch"`
+		Totals   struct {
+			Coverage json.Number   `json:"coverage"`
+			Diff     []interface{} `json:"diff"`
+		} `json:"totals"`
+	} `json:"head"`
+	Pull struct {
+		ID int `json:"id"`
+	} `json:"pull"`
+}
+
+//ToCoverageReport converts a Codecov notification into a CoverageReport.
+//Codecov reports the patch coverage as the sixth element of the head totals diff.
+func (p *CodecovWebhookPayload) ToCoverageReport() *CoverageReport {
+	report := CoverageReport{
+		Sha:       p.Head.Commitid,
+		Branch:    p.Head.Branch,
+		TargetURL: p.Head.URL,
+		Source:    "codecov",
+	}
+
+	if project, err := p.Head.Totals.Coverage.Float64(); err == nil {
+		report.ProjectCoverage = &project
+	}
+
+	if p.Pull.ID != 0 {
+		number := p.Pull.ID
+		report.PullRequestNumber = &number
+	}
+
+	if len(p.Head.Totals.Diff) > 5 && p.Head.Totals.Diff[5] != nil {
+		if patch, err := json.Number(fmt.Sprint(p.Head.Totals.Diff[5])).Float64(); err == nil {
+			report.PatchCoverage = &patch
+		}
+	}
+
+	return &report
+}
+
+//CoverageUpload represents the metadata sent along with a coverage report file.
+type CoverageUpload struct {
+	Sha               string
+	Branch            string
+	BaseBranch        string
+	PullRequestNumber *int
+	Format            string
+}
+
+//CoverageRecord represents the coverage totals of a commit.
+type CoverageRecord struct {
+	ID                *uint64 `gorm:"primary_key"`
+	ConfigurationID   *string
+	Sha               string
+	Branch            string
+	PullRequestNumber *int
+	Format            string
+	LinesCovered      int64
+	LinesValid        int64
+	Coverage          float64
+
+	//GORM date attributes
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//Marshall converts the CoverageRecord struct into a readable JSON interface.
+func (r *CoverageRecord) Marshall() interface{} {
+	return &struct {
+		Sha               string    `json:"sha"`
+		Branch            string    `json:"branch"`
+		PullRequestNumber *int      `json:"pull_request_number"`
+		Format            string    `json:"format"`
+		LinesCovered      int64     `json:"lines_covered"`
+		LinesValid        int64     `json:"lines_valid"`
+		Coverage          float64   `json:"coverage"`
+		CreatedAt         time.Time `json:"created_at"`
+	}{
+		r.Sha,
+		r.Branch,
+		r.PullRequestNumber,
+		r.Format,
+		r.LinesCovered,
+		r.LinesValid,
+		r.Coverage,
+		r.CreatedAt,
+	}
+}
+
+//ToCoverageReport converts the recorded totals into a CoverageReport.
+func (r *CoverageRecord) ToCoverageReport() *CoverageReport {
+	coverage := r.Coverage
+	return &CoverageReport{
+		Sha:               r.Sha,
+		Branch:            r.Branch,
+		PullRequestNumber: r.PullRequestNumber,
+		ProjectCoverage:   &coverage,
+		Source:            r.Format,
+	}
+}
+
+//CoveragePoint is the coverage of a commit in the history of a branch.
+type CoveragePoint struct {
+	Sha               string    `json:"sha"`
+	PullRequestNumber *int      `json:"pull_request_number"`
+	Coverage          float64   `json:"coverage"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+//CoverageHistory represents the coverage time series of every branch of a repository.
+type CoverageHistory struct {
+	Repository string                     `json:"repository"`
+	Branches   map[string][]CoveragePoint `json:"branches"`
+}
+
+//NewCoverageHistory groups the coverage records by branch, sorted by creation date.
+func NewCoverageHistory(repoName string, records []CoverageRecord) *CoverageHistory {
+	sorted := make([]CoverageRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	history := CoverageHistory{
+		Repository: repoName,
+		Branches:   make(map[string][]CoveragePoint),
+	}
+
+	for _, r := range sorted {
+		history.Branches[r.Branch] = append(history.Branches[r.Branch], CoveragePoint{
+			Sha:               r.Sha,
+			PullRequestNumber: r.PullRequestNumber,
+			Coverage:          r.Coverage,
+			CreatedAt:         r.CreatedAt,
+		})
+	}
+
+	return &history
+}
+
+//GetLatestCoverageRecord returns the latest coverage recorded for a branch, ignoring the given commit.
+//Returns nil if the branch has no coverage recorded.
+func GetLatestCoverageRecord(records []CoverageRecord, branch string, ignoreSha string) *CoverageRecord {
+	var latest *CoverageRecord
+	for i := range records {
+		r := &records[i]
+		if r.Branch != branch || r.Sha == ignoreSha {
+			continue
+		}
+		if latest == nil || latest.CreatedAt.Before(r.CreatedAt) {
+			latest = r
+		}
+	}
+	return latest
+}
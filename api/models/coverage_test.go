@@ -0,0 +1,123 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCoverageReport_Evaluate(t *testing.T) {
+	float := func(f float64) *float64 {
+		return &f
+	}
+
+	tests := []struct {
+		name         string
+		report       CoverageReport
+		threshold    *float64
+		maxDecrease  *float64
+		baseCoverage *float64
+		wantState    string
+	}{
+		{
+			name:      "project coverage above the threshold",
+			report:    CoverageReport{Sha: "a1", ProjectCoverage: float(81.5)},
+			threshold: float(80),
+			wantState: "success",
+		},
+		{
+			name:      "project coverage below the threshold",
+			report:    CoverageReport{Sha: "a1", ProjectCoverage: float(79.9)},
+			threshold: float(80),
+			wantState: "failure",
+		},
+		{
+			name:      "patch coverage below the threshold",
+			report:    CoverageReport{Sha: "a1", ProjectCoverage: float(90), PatchCoverage: float(50)},
+			threshold: float(80),
+			wantState: "failure",
+		},
+		{
+			name:      "without threshold",
+			report:    CoverageReport{Sha: "a1", ProjectCoverage: float(10)},
+			wantState: "success",
+		},
+		{
+			name:         "coverage drop within the max decrease",
+			report:       CoverageReport{Sha: "a1", ProjectCoverage: float(84), BaseBranch: "develop"},
+			threshold:    float(80),
+			maxDecrease:  float(1),
+			baseCoverage: float(84.5),
+			wantState:    "success",
+		},
+		{
+			name:         "coverage drop above the max decrease",
+			report:       CoverageReport{Sha: "a1", ProjectCoverage: float(84), BaseBranch: "develop"},
+			threshold:    float(80),
+			maxDecrease:  float(1),
+			baseCoverage: float(90),
+			wantState:    "failure",
+		},
+		{
+			name:         "coverage drop without max decrease",
+			report:       CoverageReport{Sha: "a1", ProjectCoverage: float(84), BaseBranch: "develop"},
+			threshold:    float(80),
+			baseCoverage: float(90),
+			wantState:    "success",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Evaluate(tt.threshold, tt.maxDecrease, tt.baseCoverage); got.State != tt.wantState {
+				t.Errorf("CoverageReport.Evaluate() = %v (%s), want %v", got.State, got.Description, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestCodecovWebhookPayload_ToCoverageReport(t *testing.T) {
+	payload := `{
+		"repo": {"name": "ci_cd-api", "owner": {"username": "herbal828"}},
+		"head": {"url": "https://codecov.io/c/abc", "commitid": "abc", "totals": {"coverage": "85.71", "diff": [1, 2, 1, 1, 0, "66.67", 0, 0, 0, 0, null, null, 0]}},
+		"pull": {"id": 7}
+	}`
+
+	var p CodecovWebhookPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		t.Fatalf("error binding codecov payload: %v", err)
+	}
+
+	report := p.ToCoverageReport()
+
+	if report.Sha != "abc" || *report.ProjectCoverage != 85.71 || *report.PatchCoverage != 66.67 || *report.PullRequestNumber != 7 {
+		t.Errorf("CodecovWebhookPayload.ToCoverageReport() = %+v", report)
+	}
+}
+
+func TestNewCoverageHistory(t *testing.T) {
+	now := time.Now()
+	records := []CoverageRecord{
+		{Sha: "c", Branch: "develop", Coverage: 82, CreatedAt: now.Add(2 * time.Hour)},
+		{Sha: "a", Branch: "develop", Coverage: 80, CreatedAt: now},
+		{Sha: "b", Branch: "feature/x", Coverage: 70, CreatedAt: now.Add(time.Hour)},
+	}
+
+	history := NewCoverageHistory("ci_cd-api", records)
+
+	if len(history.Branches) != 2 {
+		t.Fatalf("NewCoverageHistory() branches = %d, want 2", len(history.Branches))
+	}
+
+	develop := history.Branches["develop"]
+	if len(develop) != 2 || develop[0].Sha != "a" || develop[1].Sha != "c" {
+		t.Errorf("NewCoverageHistory() develop = %v, want commits a and c", develop)
+	}
+
+	if latest := GetLatestCoverageRecord(records, "develop", ""); latest == nil || latest.Sha != "c" {
+		t.Errorf("GetLatestCoverageRecord() = %v, want c", latest)
+	}
+
+	if latest := GetLatestCoverageRecord(records, "develop", "c"); latest == nil || latest.Sha != "a" {
+		t.Errorf("GetLatestCoverageRecord() ignoring c = %v, want a", latest)
+	}
+}
@@ -0,0 +1,220 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+//Deployment states, they match the Github deployment statuses
+const (
+	DeploymentStatePending    = "pending"
+	DeploymentStateInProgress = "in_progress"
+	DeploymentStateSuccess    = "success"
+	DeploymentStateFailure    = "failure"
+	DeploymentStateError      = "error"
+)
+
+//Deployment states of the approval gates, the deployment is not created on Github while it is in one of them
+const (
+	DeploymentStateAwaitingApproval = "awaiting_approval"
+	DeploymentStateRejected         = "rejected"
+)
+
+//PostDeploymentRequestPayload represents the request body needed to deploy a repository environment.
+//The ref is optional, by default the environment ref is deployed.
+type PostDeploymentRequestPayload struct {
+	Environment string  `json:"environment"`
+	Ref         *string `json:"ref"`
+}
+
+//PostDeploymentStatusRequestPayload represents the request body needed to report the progress of a deployment.
+type PostDeploymentStatusRequestPayload struct {
+	State       string `json:"state"`
+	Description string `json:"description"`
+	LogURL      string `json:"log_url"`
+}
+
+//Deployment represents a deployment of a repository ref into one of its environments.
+//It is mirrored as a Github deployment and keeps the history of its statuses.
+type Deployment struct {
+	ID                 *uint64 `gorm:"primary_key"`
+	ConfigurationID    *string
+	Environment        string
+	Ref                string
+	Sha                string
+	State              string
+	GithubDeploymentID *int64
+	RollbackToID       *uint64
+	Statuses           []DeploymentStatus
+	Approvals          []Approval
+
+	//GORM date attributes
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//DeploymentStatus is an entry of the status history of a deployment.
+type DeploymentStatus struct {
+	ID           *uint64 `gorm:"primary_key"`
+	DeploymentID *uint64
+	State        string
+	Description  string
+	LogURL       string
+
+	//GORM date attributes
+	CreatedAt time.Time
+}
+
+//IsValidDeploymentState reports if the state is one of the deployment states.
+func IsValidDeploymentState(state string) bool {
+	switch state {
+	case DeploymentStatePending, DeploymentStateInProgress, DeploymentStateSuccess, DeploymentStateFailure, DeploymentStateError:
+		return true
+	}
+	return false
+}
+
+//NewDeployment returns a new Deployment of the ref into the environment.
+//If the environment requires approvals the deployment awaits them, otherwise it has to be started.
+func NewDeployment(config *Configuration, env *Environment, ref string) *Deployment {
+	d := &Deployment{
+		ConfigurationID: config.ID,
+		Environment:     env.Name,
+		Ref:             ref,
+	}
+	if env.RequiredApprovals > 0 {
+		d.AddStatus(DeploymentStateAwaitingApproval, fmt.Sprintf("Waiting for %d approvals", env.RequiredApprovals), "")
+	}
+	return d
+}
+
+//NewRollbackDeployment returns a new Deployment which re-deploys the commit of a previous deployment.
+//The commit is deployed as the ref, and like any other deployment the rollback awaits the approvals
+//the environment requires.
+func NewRollbackDeployment(config *Configuration, env *Environment, target *Deployment) *Deployment {
+	d := NewDeployment(config, env, target.Sha)
+	d.RollbackToID = target.ID
+	return d
+}
+
+//GetDeployRef returns the ref to deploy, rollbacks deploy the exact commit of the deployment they restore.
+func (d *Deployment) GetDeployRef() string {
+	if d.RollbackToID != nil && d.Sha != "" {
+		return d.Sha
+	}
+	return d.Ref
+}
+
+//GetRollbackTarget returns the latest successful deployment of the environment whose commit is not
+//the one currently deployed, or nil if there is none.
+//The current commit is the one of the latest deployment which reached the environment, the deployments
+//which never started (awaiting approval or rejected) have no commit and are skipped.
+func GetRollbackTarget(deployments []Deployment) *Deployment {
+	sorted := make([]Deployment, len(deployments))
+	copy(sorted, deployments)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return *sorted[i].ID > *sorted[j].ID
+	})
+
+	current := -1
+	for i := range sorted {
+		if sorted[i].Sha != "" {
+			current = i
+			break
+		}
+	}
+
+	if current < 0 {
+		return nil
+	}
+
+	for i := current + 1; i < len(sorted); i++ {
+		if sorted[i].State == DeploymentStateSuccess && sorted[i].Sha != sorted[current].Sha {
+			return &sorted[i]
+		}
+	}
+
+	return nil
+}
+
+//Start links the deployment with the Github deployment created for it and moves it to pending.
+func (d *Deployment) Start(gd *GithubDeployment) {
+	d.Sha = gd.Sha
+	d.GithubDeploymentID = &gd.ID
+	d.AddStatus(DeploymentStatePending, "Deployment created", "")
+}
+
+//AddStatus appends a status to the deployment history and moves the deployment to its state.
+func (d *Deployment) AddStatus(state string, description string, logURL string) *DeploymentStatus {
+	d.State = state
+	d.Statuses = append(d.Statuses, DeploymentStatus{
+		DeploymentID: d.ID,
+		State:        state,
+		Description:  description,
+		LogURL:       logURL,
+		CreatedAt:    time.Now().UTC(),
+	})
+	return &d.Statuses[len(d.Statuses)-1]
+}
+
+//IsFinished reports if the deployment reached a final state.
+func (d *Deployment) IsFinished() bool {
+	switch d.State {
+	case DeploymentStateSuccess, DeploymentStateFailure, DeploymentStateError, DeploymentStateRejected:
+		return true
+	}
+	return false
+}
+
+//Marshall converts the Deployment struct into a readable JSON interface.
+func (d *Deployment) Marshall() interface{} {
+	statuses := make([]interface{}, 0)
+	for _, st := range d.Statuses {
+		statuses = append(statuses, st.Marshall())
+	}
+
+	approvals := make([]interface{}, 0)
+	for _, a := range d.Approvals {
+		approvals = append(approvals, a.Marshall())
+	}
+
+	return &struct {
+		ID          *uint64       `json:"id"`
+		Environment string        `json:"environment"`
+		Ref         string        `json:"ref"`
+		Sha         string        `json:"sha"`
+		State       string        `json:"state"`
+		RollbackTo  *uint64       `json:"rollback_to"`
+		Statuses    []interface{} `json:"statuses"`
+		Approvals   []interface{} `json:"approvals"`
+		CreatedAt   time.Time     `json:"created_at"`
+		UpdatedAt   time.Time     `json:"updated_at"`
+	}{
+		d.ID,
+		d.Environment,
+		d.Ref,
+		d.Sha,
+		d.State,
+		d.RollbackToID,
+		statuses,
+		approvals,
+		d.CreatedAt,
+		d.UpdatedAt,
+	}
+}
+
+//Marshall converts the DeploymentStatus struct into a readable JSON interface.
+func (s *DeploymentStatus) Marshall() interface{} {
+	return &struct {
+		State       string    `json:"state"`
+		Description string    `json:"description"`
+		LogURL      string    `json:"log_url"`
+		CreatedAt   time.Time `json:"created_at"`
+	}{
+		s.State,
+		s.Description,
+		s.LogURL,
+		s.CreatedAt,
+	}
+}
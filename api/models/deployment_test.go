@@ -0,0 +1,160 @@
+package models
+
+import (
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfiguration_GetEnvironments(t *testing.T) {
+	c := &Configuration{
+		Environments: []Environment{
+			{Name: "production", Ref: "master", Position: 1, RequiredApprovals: 2},
+			{Name: "staging", Ref: "develop", Position: 0},
+		},
+	}
+
+	envs := c.GetEnvironments()
+	assert.Equal(t, "staging", envs[0].Name)
+	assert.Equal(t, "production", envs[1].Name)
+
+	assert.Equal(t, 2, c.GetEnvironment("production").RequiredApprovals)
+	assert.Nil(t, c.GetEnvironment("qa"))
+}
+
+func TestNewEnvironments(t *testing.T) {
+	envs := NewEnvironments([]EnvironmentPayload{
+		{Name: "staging", Ref: "develop"},
+		{Name: "production", Ref: "master", RequiredApprovals: 1, Approvers: []string{"octocat"}, ApprovalTTLMinutes: 60},
+	})
+
+	assert.Equal(t, []Environment{
+		{Name: "staging", Ref: "develop", Position: 0, Approvers: []EnvironmentApprover{}},
+		{Name: "production", Ref: "master", Position: 1, RequiredApprovals: 1, Approvers: []EnvironmentApprover{{Login: "octocat"}}, ApprovalTTLMinutes: 60},
+	}, envs)
+}
+
+func TestDeployment_AddStatus(t *testing.T) {
+	config := &Configuration{ID: utils.Stringify("ci_cd-api")}
+	env := &Environment{Name: "staging", Ref: "develop"}
+	d := NewDeployment(config, env, "develop")
+	assert.Empty(t, d.State)
+
+	d.Start(&GithubDeployment{ID: 7, Sha: "abc123"})
+
+	assert.Equal(t, DeploymentStatePending, d.State)
+	assert.Equal(t, "abc123", d.Sha)
+	assert.Equal(t, int64(7), *d.GithubDeploymentID)
+	assert.False(t, d.IsFinished())
+
+	d.AddStatus(DeploymentStateInProgress, "Rolling out", "http://cd/1")
+	d.AddStatus(DeploymentStateSuccess, "Deployed", "http://cd/1")
+
+	assert.Equal(t, DeploymentStateSuccess, d.State)
+	assert.True(t, d.IsFinished())
+	assert.Len(t, d.Statuses, 3)
+	assert.Equal(t, DeploymentStateInProgress, d.Statuses[1].State)
+}
+
+func TestIsValidDeploymentState(t *testing.T) {
+	assert.True(t, IsValidDeploymentState(DeploymentStateInProgress))
+	assert.False(t, IsValidDeploymentState("running"))
+}
+
+func TestDeployment_CountValidApprovals(t *testing.T) {
+	now := time.Now().UTC()
+	config := &Configuration{ID: utils.Stringify("ci_cd-api")}
+	env := &Environment{Name: "production", Ref: "master", RequiredApprovals: 2}
+
+	d := NewDeployment(config, env, "master")
+	assert.Equal(t, DeploymentStateAwaitingApproval, d.State)
+
+	d.Approvals = []Approval{
+		{User: "octocat", Decision: ApprovalDecisionApprove, ExpiresAt: now.Add(time.Hour)},
+		{User: "octocat", Decision: ApprovalDecisionApprove, ExpiresAt: now.Add(2 * time.Hour)},
+		{User: "hubot", Decision: ApprovalDecisionApprove, ExpiresAt: now.Add(-time.Minute)},
+		{User: "monalisa", Decision: ApprovalDecisionReject, Reason: "not yet", ExpiresAt: now.Add(time.Hour)},
+	}
+
+	assert.Equal(t, 1, d.CountValidApprovals(now))
+	assert.True(t, d.HasValidApproval("octocat", now))
+	assert.False(t, d.HasValidApproval("hubot", now))
+	assert.False(t, d.HasValidApproval("monalisa", now))
+}
+
+func TestGetRollbackTarget(t *testing.T) {
+	id := func(i uint64) *uint64 { return &i }
+
+	tests := []struct {
+		name        string
+		deployments []Deployment
+		wantID      *uint64
+	}{
+		{
+			name:        "test - no deployments",
+			deployments: []Deployment{},
+		},
+		{
+			name: "test - failed deployment rolls back to the previous success",
+			deployments: []Deployment{
+				{ID: id(1), Sha: "aaa", State: DeploymentStateSuccess},
+				{ID: id(3), Sha: "ccc", State: DeploymentStateFailure},
+				{ID: id(2), Sha: "bbb", State: DeploymentStateSuccess},
+			},
+			wantID: id(2),
+		},
+		{
+			name: "test - successful bad deployment skips its own commit",
+			deployments: []Deployment{
+				{ID: id(1), Sha: "aaa", State: DeploymentStateSuccess},
+				{ID: id(2), Sha: "bbb", State: DeploymentStateSuccess},
+				{ID: id(3), Sha: "bbb", State: DeploymentStateSuccess},
+			},
+			wantID: id(1),
+		},
+		{
+			name: "test - deployments which never started are skipped",
+			deployments: []Deployment{
+				{ID: id(1), Sha: "aaa", State: DeploymentStateSuccess},
+				{ID: id(2), Sha: "bbb", State: DeploymentStateSuccess},
+				{ID: id(3), State: DeploymentStateRejected},
+				{ID: id(4), State: DeploymentStateAwaitingApproval},
+			},
+			wantID: id(1),
+		},
+		{
+			name: "test - nothing to roll back to",
+			deployments: []Deployment{
+				{ID: id(1), Sha: "aaa", State: DeploymentStateFailure},
+				{ID: id(2), Sha: "bbb", State: DeploymentStateSuccess},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetRollbackTarget(tt.deployments)
+			if tt.wantID == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, *tt.wantID, *got.ID)
+		})
+	}
+}
+
+func TestNewRollbackDeployment(t *testing.T) {
+	target := &Deployment{ID: func(i uint64) *uint64 { return &i }(4), Environment: "production", Ref: "master", Sha: "abc123"}
+	d := NewRollbackDeployment(&Configuration{ID: utils.Stringify("ci_cd-api")}, &Environment{Name: "production"}, target)
+
+	assert.Equal(t, "production", d.Environment)
+	assert.Equal(t, "abc123", d.GetDeployRef())
+	assert.Equal(t, uint64(4), *d.RollbackToID)
+	assert.Equal(t, "", d.State)
+
+	d = NewRollbackDeployment(&Configuration{ID: utils.Stringify("ci_cd-api")}, &Environment{Name: "production", RequiredApprovals: 2}, target)
+
+	assert.Equal(t, DeploymentStateAwaitingApproval, d.State)
+	assert.Equal(t, "abc123", d.GetDeployRef())
+}
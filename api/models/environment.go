@@ -0,0 +1,208 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+//Deployment targets of the environments
+const (
+	//EnvironmentTargetGithub leaves the deployments to the CD backends listening to the Github deployment events
+	EnvironmentTargetGithub = "github"
+	//EnvironmentTargetWebhook delivers the deployments to an HTTP endpoint signed with the target secret
+	EnvironmentTargetWebhook = "webhook"
+	//EnvironmentTargetKubernetes updates the image of a Kubernetes Deployment through the API server
+	EnvironmentTargetKubernetes = "kubernetes"
+)
+
+//DefaultApprovalTTLMinutes is how long an approval is valid when the environment does not set it.
+const DefaultApprovalTTLMinutes = 24 * 60
+
+//EnvironmentPayload represents an environment of the deployment pipeline received in the configuration payloads.
+type EnvironmentPayload struct {
+	Name               string   `json:"name"`
+	Ref                string   `json:"ref"`
+	RequiredApprovals  int      `json:"required_approvals"`
+	Approvers          []string `json:"approvers"`
+	ApprovalTTLMinutes int      `json:"approval_ttl_minutes"`
+	Target             struct {
+		Type       string `json:"type"`
+		URL        string `json:"url"`
+		SecretRef  string `json:"secret_ref"`
+		Namespace  string `json:"namespace"`
+		Deployment string `json:"deployment"`
+		Container  string `json:"container"`
+		Image      string `json:"image"`
+	} `json:"target"`
+}
+
+//Environment is a stage of the repository deployment pipeline (staging, production, etc).
+//The environments are deployed in the order given by Position, each one from its own branch or tag
+//and only after the number of approvals it requires from its approvers.
+//The secret of the deployment target is not stored, only the reference it is resolved from.
+type Environment struct {
+	ID                 *uint64 `gorm:"primary_key"`
+	ConfigurationID    *string
+	Name               string
+	Ref                string
+	Position           int
+	RequiredApprovals  int
+	Approvers          []EnvironmentApprover
+	ApprovalTTLMinutes int
+	TargetType         string
+	TargetURL          string
+	TargetSecretRef    string
+	TargetNamespace    string
+	TargetDeployment   string
+	TargetContainer    string
+	TargetImage        string
+}
+
+//EnvironmentApprover is a Github user authorized to approve the deployments of an environment.
+type EnvironmentApprover struct {
+	ID            *uint64 `gorm:"primary_key"`
+	EnvironmentID *uint64
+	Login         string
+}
+
+//NewEnvironments converts the environments of a configuration payload keeping their order.
+func NewEnvironments(payload []EnvironmentPayload) []Environment {
+	envs := make([]Environment, 0)
+	for i, e := range payload {
+		approvers := make([]EnvironmentApprover, 0)
+		for _, login := range e.Approvers {
+			approvers = append(approvers, EnvironmentApprover{
+				Login: login,
+			})
+		}
+
+		envs = append(envs, Environment{
+			Name:               e.Name,
+			Ref:                e.Ref,
+			Position:           i,
+			RequiredApprovals:  e.RequiredApprovals,
+			Approvers:          approvers,
+			ApprovalTTLMinutes: e.ApprovalTTLMinutes,
+			TargetType:         e.Target.Type,
+			TargetURL:          e.Target.URL,
+			TargetSecretRef:    e.Target.SecretRef,
+			TargetNamespace:    e.Target.Namespace,
+			TargetDeployment:   e.Target.Deployment,
+			TargetContainer:    e.Target.Container,
+			TargetImage:        e.Target.Image,
+		})
+	}
+	return envs
+}
+
+//ToPayload converts the environment into its configuration payload representation.
+func (e *Environment) ToPayload() EnvironmentPayload {
+	approvers := make([]string, 0)
+	for _, a := range e.Approvers {
+		approvers = append(approvers, a.Login)
+	}
+
+	var p EnvironmentPayload
+	p.Name = e.Name
+	p.Ref = e.Ref
+	p.RequiredApprovals = e.RequiredApprovals
+	p.Approvers = approvers
+	p.ApprovalTTLMinutes = e.ApprovalTTLMinutes
+	p.Target.Type = e.TargetType
+	p.Target.URL = e.TargetURL
+	p.Target.SecretRef = e.TargetSecretRef
+	p.Target.Namespace = e.TargetNamespace
+	p.Target.Deployment = e.TargetDeployment
+	p.Target.Container = e.TargetContainer
+	p.Target.Image = e.TargetImage
+	return p
+}
+
+//GetEnvironments returns the environments of the deployment pipeline in order.
+func (c *Configuration) GetEnvironments() []Environment {
+	envs := make([]Environment, len(c.Environments))
+	copy(envs, c.Environments)
+	sort.SliceStable(envs, func(i, j int) bool {
+		return envs[i].Position < envs[j].Position
+	})
+	return envs
+}
+
+//GetEnvironment returns the environment of the deployment pipeline with the given name.
+func (c *Configuration) GetEnvironment(name string) *Environment {
+	for i := range c.Environments {
+		if c.Environments[i].Name == name {
+			return &c.Environments[i]
+		}
+	}
+	return nil
+}
+
+//IsApprover reports if the Github user is authorized to approve the deployments of the environment.
+func (e *Environment) IsApprover(login string) bool {
+	for _, a := range e.Approvers {
+		if a.Login == login {
+			return true
+		}
+	}
+	return false
+}
+
+//GetTargetType returns the deployment target of the environment, Github by default.
+func (e *Environment) GetTargetType() string {
+	if e.TargetType == "" {
+		return EnvironmentTargetGithub
+	}
+	return e.TargetType
+}
+
+//GetApprovalTTL returns how long an approval of the environment deployments is valid.
+func (e *Environment) GetApprovalTTL() time.Duration {
+	ttl := e.ApprovalTTLMinutes
+	if ttl <= 0 {
+		ttl = DefaultApprovalTTLMinutes
+	}
+	return time.Duration(ttl) * time.Minute
+}
+
+//Marshall converts the Environment struct into a readable JSON interface.
+func (e *Environment) Marshall() interface{} {
+	approvers := make([]string, 0)
+	for _, a := range e.Approvers {
+		approvers = append(approvers, a.Login)
+	}
+
+	target := struct {
+		Type       string `json:"type"`
+		URL        string `json:"url,omitempty"`
+		SecretRef  string `json:"secret_ref,omitempty"`
+		Namespace  string `json:"namespace,omitempty"`
+		Deployment string `json:"deployment,omitempty"`
+		Container  string `json:"container,omitempty"`
+		Image      string `json:"image,omitempty"`
+	}{
+		e.GetTargetType(),
+		e.TargetURL,
+		e.TargetSecretRef,
+		e.TargetNamespace,
+		e.TargetDeployment,
+		e.TargetContainer,
+		e.TargetImage,
+	}
+
+	return &struct {
+		Name               string      `json:"name"`
+		Ref                string      `json:"ref"`
+		RequiredApprovals  int         `json:"required_approvals"`
+		Approvers          []string    `json:"approvers"`
+		ApprovalTTLMinutes int         `json:"approval_ttl_minutes"`
+		Target             interface{} `json:"target"`
+	}{
+		e.Name,
+		e.Ref,
+		e.RequiredApprovals,
+		approvers,
+		int(e.GetApprovalTTL() / time.Minute),
+		target,
+	}
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type BranchProtectionResponse struct {
 	URL                  string `json:"url"`
 	RequiredStatusChecks struct {
@@ -42,3 +44,164 @@ type GetBranchResponse struct {
 	} `json:"commit"`
 	Protected bool `json:"protected"`
 }
+
+type Webhook struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Active bool     `json:"active"`
+	Events []string `json:"events"`
+	Config struct {
+		URL         string `json:"url"`
+		ContentType string `json:"content_type"`
+	} `json:"config"`
+}
+
+type PullRequest struct {
+	Number  int    `json:"number"`
+	State   string `json:"state"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	Merged  bool   `json:"merged"`
+	Head    struct {
+		Ref string `json:"ref"`
+		Sha string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	MergeCommitSha string `json:"merge_commit_sha"`
+	MergedAt       string `json:"merged_at"`
+	User           struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type MergePullRequestResponse struct {
+	Sha     string `json:"sha"`
+	Merged  bool   `json:"merged"`
+	Message string `json:"message"`
+}
+
+type CombinedStatus struct {
+	State    string `json:"state"`
+	Sha      string `json:"sha"`
+	Statuses []struct {
+		Context     string `json:"context"`
+		State       string `json:"state"`
+		Description string `json:"description"`
+		TargetURL   string `json:"target_url"`
+	} `json:"statuses"`
+}
+
+type GitTag struct {
+	Tag     string `json:"tag"`
+	Sha     string `json:"sha"`
+	Message string `json:"message"`
+}
+
+type GithubRelease struct {
+	ID      int64  `json:"id"`
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	HTMLURL string `json:"html_url"`
+}
+
+//GetPendingContexts returns the required contexts which are not reported as success for the commit.
+func (s *CombinedStatus) GetPendingContexts(required []string) []string {
+	succeeded := make(map[string]bool)
+	for _, st := range s.Statuses {
+		if st.State == "success" {
+			succeeded[st.Context] = true
+		}
+	}
+
+	pending := make([]string, 0)
+	for _, context := range required {
+		if !succeeded[context] {
+			pending = append(pending, context)
+		}
+	}
+
+	return pending
+}
+
+type Tag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		Sha string `json:"sha"`
+	} `json:"commit"`
+}
+
+type Commit struct {
+	Sha    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+			Date string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Parents []struct {
+		Sha string `json:"sha"`
+	} `json:"parents"`
+}
+
+type CompareResponse struct {
+	Status       string   `json:"status"`
+	AheadBy      int      `json:"ahead_by"`
+	BehindBy     int      `json:"behind_by"`
+	TotalCommits int      `json:"total_commits"`
+	Commits      []Commit `json:"commits"`
+}
+
+type CommitStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+type CheckRun struct {
+	ID          *int64          `json:"id,omitempty"`
+	Name        string          `json:"name"`
+	HeadSha     string          `json:"head_sha,omitempty"`
+	Status      string          `json:"status"`
+	Conclusion  string          `json:"conclusion,omitempty"`
+	DetailsURL  string          `json:"details_url,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	Output      *CheckRunOutput `json:"output,omitempty"`
+}
+
+type CheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+type CheckRunList struct {
+	TotalCount int        `json:"total_count"`
+	CheckRuns  []CheckRun `json:"check_runs"`
+}
+
+type GithubDeployment struct {
+	ID          int64  `json:"id"`
+	Sha         string `json:"sha"`
+	Ref         string `json:"ref"`
+	Environment string `json:"environment"`
+}
+
+type GithubDeploymentStatus struct {
+	State       string `json:"state"`
+	LogURL      string `json:"log_url,omitempty"`
+	Description string `json:"description"`
+}
+
+//GithubUser represents a Github user.
+type GithubUser struct {
+	Login string `json:"login"`
+}
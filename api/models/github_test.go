@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCombinedStatus_GetPendingContexts(t *testing.T) {
+	var status CombinedStatus
+	payload := `{"state":"pending","statuses":[{"context":"ci","state":"success"},{"context":"coverage","state":"failure"}]}`
+	if err := json.Unmarshal([]byte(payload), &status); err != nil {
+		t.Fatalf("error binding combined status: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		required []string
+		want     []string
+	}{
+		{
+			name:     "no required contexts",
+			required: nil,
+			want:     []string{},
+		},
+		{
+			name:     "all required contexts are successful",
+			required: []string{"ci"},
+			want:     []string{},
+		},
+		{
+			name:     "failed and missing contexts",
+			required: []string{"ci", "coverage", "lint"},
+			want:     []string{"coverage", "lint"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.GetPendingContexts(tt.required); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CombinedStatus.GetPendingContexts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
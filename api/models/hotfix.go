@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+//Hotfix states
+//A hotfix goes through every state in this order, each one is persisted as soon as its step is completed
+//so a failed hotfix can be resumed.
+const (
+	HotfixStateCreated         = "created"
+	HotfixStateMasterPrOpened  = "master_pr_opened"
+	HotfixStateMasterMerged    = "master_merged"
+	HotfixStateTagged          = "tagged"
+	HotfixStateDevelopPrOpened = "develop_pr_opened"
+	HotfixStateFinished        = "finished"
+)
+
+//Hotfix represents a gitflow hotfix of a repository.
+//It is started by creating a hotfix/x.y.z branch from the latest master tag.
+type Hotfix struct {
+	ID              *uint64 `gorm:"primary_key"`
+	ConfigurationID *string
+	Version         string
+	Branch          string
+	BaseTag         string
+	BaseSha         string
+	State           string
+
+	//Finalization steps
+	MasterPullRequestNumber  *int
+	MasterMergeSha           string
+	TagSha                   string
+	DevelopPullRequestNumber *int
+	DevelopMergeSha          string
+
+	//GORM date attributes
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//NewHotfix initializes a Hotfix for the given configuration, starting from the given tag.
+func NewHotfix(config *Configuration, version *Version, baseTag *Tag) *Hotfix {
+	return &Hotfix{
+		ConfigurationID: config.ID,
+		Version:         version.String(),
+		Branch:          "hotfix/" + version.String(),
+		BaseTag:         baseTag.Name,
+		BaseSha:         baseTag.Commit.Sha,
+		State:           HotfixStateCreated,
+	}
+}
+
+//Tag returns the name of the git tag which identifies the hotfix.
+func (h *Hotfix) Tag() string {
+	return "v" + h.Version
+}
+
+//Marshall converts the Hotfix struct into a readable JSON interface.
+func (h *Hotfix) Marshall() interface{} {
+	return &struct {
+		ID                       uint64    `json:"id"`
+		Version                  string    `json:"version"`
+		Branch                   string    `json:"branch"`
+		BaseTag                  string    `json:"base_tag"`
+		BaseSha                  string    `json:"base_sha"`
+		State                    string    `json:"state"`
+		MasterPullRequestNumber  *int      `json:"master_pull_request_number"`
+		MasterMergeSha           string    `json:"master_merge_sha"`
+		Tag                      string    `json:"tag"`
+		TagSha                   string    `json:"tag_sha"`
+		DevelopPullRequestNumber *int      `json:"develop_pull_request_number"`
+		DevelopMergeSha          string    `json:"develop_merge_sha"`
+		CreatedAt                time.Time `json:"created_at"`
+		UpdatedAt                time.Time `json:"updated_at"`
+	}{
+		*h.ID,
+		h.Version,
+		h.Branch,
+		h.BaseTag,
+		h.BaseSha,
+		h.State,
+		h.MasterPullRequestNumber,
+		h.MasterMergeSha,
+		h.Tag(),
+		h.TagSha,
+		h.DevelopPullRequestNumber,
+		h.DevelopMergeSha,
+		h.CreatedAt,
+		h.UpdatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+//States of the idempotency keys
+const (
+	IdempotencyKeyStateInProgress = "in_progress"
+	IdempotencyKeyStateCompleted  = "completed"
+)
+
+const (
+	//IdempotencyKeyHeader is the request header which carries the idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	//IdempotencyKeyTTL is the time a completed request is replayed for its key.
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	//IdempotencyKeyLockTimeout is the time after which a request still in progress is considered abandoned.
+	IdempotencyKeyLockTimeout = 5 * time.Minute
+)
+
+//IdempotencyReplayedHeaders are the response headers stored along with the response to be replayed,
+//so the retries get the ETag to update the resource with and the location of the created one.
+var IdempotencyReplayedHeaders = []string{"ETag", "Location", "Link"}
+
+//IdempotencyKey is a request identified by the client through the Idempotency-Key header.
+//It keeps the fingerprint of the request and, once it is completed, its response so retries replay it.
+type IdempotencyKey struct {
+	ID              *uint64 `gorm:"primary_key"`
+	Key             string  `gorm:"unique_index"`
+	Fingerprint     string
+	State           string
+	ResponseStatus  int
+	ResponseBody    string `gorm:"type:text"`
+	ResponseHeaders string `gorm:"type:text"`
+
+	//GORM date attributes
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//NewIdempotencyKey returns a new IdempotencyKey in progress for the given request.
+func NewIdempotencyKey(key string, method string, path string, body []byte) *IdempotencyKey {
+	return &IdempotencyKey{
+		Key:         key,
+		Fingerprint: GetRequestFingerprint(method, path, body),
+		State:       IdempotencyKeyStateInProgress,
+	}
+}
+
+//GetRequestFingerprint returns the SHA-256 of the request method, path and body.
+//Requests which reuse a key are only replayed when they have the same fingerprint.
+func GetRequestFingerprint(method string, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//Complete stores the response of the request along with its replayed headers.
+func (k *IdempotencyKey) Complete(status int, body []byte, header http.Header) {
+	k.State = IdempotencyKeyStateCompleted
+	k.ResponseStatus = status
+	k.ResponseBody = string(body)
+
+	replayed := make(map[string]string)
+	for _, name := range IdempotencyReplayedHeaders {
+		if value := header.Get(name); value != "" {
+			replayed[name] = value
+		}
+	}
+	headers, _ := json.Marshal(replayed)
+	k.ResponseHeaders = string(headers)
+}
+
+//GetResponseHeaders returns the stored headers of the response.
+func (k *IdempotencyKey) GetResponseHeaders() map[string]string {
+	headers := make(map[string]string)
+	if k.ResponseHeaders != "" {
+		json.Unmarshal([]byte(k.ResponseHeaders), &headers)
+	}
+	return headers
+}
+
+//IsCompleted reports if the request already has a response to replay.
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.State == IdempotencyKeyStateCompleted
+}
+
+//IsExpired reports if the key can be reused at the given time, because its response is no longer replayed
+//or because its request was abandoned while in progress.
+func (k *IdempotencyKey) IsExpired(now time.Time) bool {
+	if k.IsCompleted() {
+		return now.After(k.UpdatedAt.Add(IdempotencyKeyTTL))
+	}
+	return now.After(k.UpdatedAt.Add(IdempotencyKeyLockTimeout))
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKey_IsExpired(t *testing.T) {
+	updatedAt := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		state string
+		now   time.Time
+		want  bool
+	}{
+		{
+			name:  "test - request in progress",
+			state: IdempotencyKeyStateInProgress,
+			now:   updatedAt.Add(time.Minute),
+			want:  false,
+		},
+		{
+			name:  "test - request abandoned in progress",
+			state: IdempotencyKeyStateInProgress,
+			now:   updatedAt.Add(IdempotencyKeyLockTimeout + time.Second),
+			want:  true,
+		},
+		{
+			name:  "test - completed request is replayed",
+			state: IdempotencyKeyStateCompleted,
+			now:   updatedAt.Add(time.Hour),
+			want:  false,
+		},
+		{
+			name:  "test - completed request is no longer replayed",
+			state: IdempotencyKeyStateCompleted,
+			now:   updatedAt.Add(IdempotencyKeyTTL + time.Second),
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := IdempotencyKey{State: tt.state, UpdatedAt: updatedAt}
+			assert.Equal(t, tt.want, k.IsExpired(tt.now))
+		})
+	}
+}
+
+func TestIdempotencyKey_Complete(t *testing.T) {
+	header := make(http.Header)
+	header.Set("ETag", `"3"`)
+	header.Set("Location", "/configurations/acme/api")
+	header.Set("Content-Type", "application/json")
+
+	k := NewIdempotencyKey("k1", "POST", "/configurations", []byte(`{"a":1}`))
+	k.Complete(http.StatusCreated, []byte(`{"id":"acme/api"}`), header)
+
+	assert.True(t, k.IsCompleted())
+	assert.Equal(t, map[string]string{"ETag": `"3"`, "Location": "/configurations/acme/api"}, k.GetResponseHeaders())
+}
+
+func TestGetRequestFingerprint(t *testing.T) {
+	f := GetRequestFingerprint("POST", "/configurations", []byte(`{"a":1}`))
+
+	assert.Equal(t, f, GetRequestFingerprint("POST", "/configurations", []byte(`{"a":1}`)))
+	assert.NotEqual(t, f, GetRequestFingerprint("PUT", "/configurations", []byte(`{"a":1}`)))
+	assert.NotEqual(t, f, GetRequestFingerprint("POST", "/configurations", []byte(`{"a":2}`)))
+}
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+//Release states
+//A release goes through every state in this order, each one is persisted as soon as its step is completed
+//so an interrupted finalization can be resumed.
+const (
+	ReleaseStateCreated   = "created"
+	ReleaseStatePrOpened  = "pr_opened"
+	ReleaseStateMerged    = "merged"
+	ReleaseStateTagged    = "tagged"
+	ReleaseStatePublished = "published"
+	ReleaseStateFinished  = "finished"
+)
+
+//PostReleaseRequestPayload represents the payload received in the POST release request.
+//Both fields are optional, by default the bump is computed from the conventional commits of develop.
+type PostReleaseRequestPayload struct {
+	Bump    *string `json:"bump"`
+	Version *string `json:"version"`
+}
+
+//Release represents a gitflow release of a repository.
+//It is started by creating a release/x.y.z branch from develop.
+type Release struct {
+	ID              *uint64 `gorm:"primary_key"`
+	ConfigurationID *string
+	Version         string
+	Branch          string
+	BaseSha         string
+	State           string
+
+	//Finalization steps
+	PullRequestNumber          *int
+	MergeSha                   string
+	TagSha                     string
+	GithubReleaseID            *int64
+	BackMergePullRequestNumber *int
+
+	//GORM date attributes
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//NewRelease initializes a Release for the given configuration and version.
+func NewRelease(config *Configuration, version *Version, baseSha string) *Release {
+	return &Release{
+		ConfigurationID: config.ID,
+		Version:         version.String(),
+		Branch:          "release/" + version.String(),
+		BaseSha:         baseSha,
+		State:           ReleaseStateCreated,
+	}
+}
+
+//Tag returns the name of the git tag which identifies the release.
+func (r *Release) Tag() string {
+	return "v" + r.Version
+}
+
+//Marshall converts the Release struct into a readable JSON interface.
+func (r *Release) Marshall() interface{} {
+	return &struct {
+		ID                         uint64    `json:"id"`
+		Version                    string    `json:"version"`
+		Branch                     string    `json:"branch"`
+		BaseSha                    string    `json:"base_sha"`
+		State                      string    `json:"state"`
+		PullRequestNumber          *int      `json:"pull_request_number"`
+		MergeSha                   string    `json:"merge_sha"`
+		Tag                        string    `json:"tag"`
+		TagSha                     string    `json:"tag_sha"`
+		GithubReleaseID            *int64    `json:"github_release_id"`
+		BackMergePullRequestNumber *int      `json:"back_merge_pull_request_number"`
+		CreatedAt                  time.Time `json:"created_at"`
+		UpdatedAt                  time.Time `json:"updated_at"`
+	}{
+		*r.ID,
+		r.Version,
+		r.Branch,
+		r.BaseSha,
+		r.State,
+		r.PullRequestNumber,
+		r.MergeSha,
+		r.Tag(),
+		r.TagSha,
+		r.GithubReleaseID,
+		r.BackMergePullRequestNumber,
+		r.CreatedAt,
+		r.UpdatedAt,
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+//Check run statuses and conclusions of the Github Checks API
+const (
+	CheckRunStatusQueued     = "queued"
+	CheckRunStatusInProgress = "in_progress"
+	CheckRunStatusCompleted  = "completed"
+)
+
+//maxStatusDescriptionLength is the longest description accepted by Github for a commit status.
+const maxStatusDescriptionLength = 140
+
+//StatusUpdate represents the state of a check of a commit to be published on Github,
+//either as a commit status or as a check run.
+//The state is one of the internal build states: pending, running, success, failure or error.
+type StatusUpdate struct {
+	Context     string
+	State       string
+	Description string
+	TargetURL   string
+}
+
+//ToCommitStatus converts the update into a Github commit status.
+//Commit statuses do not have a running state, so it is reported as pending.
+func (u *StatusUpdate) ToCommitStatus() *CommitStatus {
+	state := u.State
+	if state == BuildStateRunning {
+		state = BuildStatePending
+	}
+
+	return &CommitStatus{
+		State:       state,
+		TargetURL:   u.TargetURL,
+		Description: u.shortDescription(),
+		Context:     u.Context,
+	}
+}
+
+//ToCheckRun converts the update into a Github check run of the given commit.
+func (u *StatusUpdate) ToCheckRun(sha string) *CheckRun {
+	run := &CheckRun{
+		Name:       u.Context,
+		HeadSha:    sha,
+		DetailsURL: u.TargetURL,
+		Output: &CheckRunOutput{
+			Title:   u.shortDescription(),
+			Summary: u.Description,
+		},
+	}
+
+	switch u.State {
+	case BuildStateRunning:
+		run.Status = CheckRunStatusInProgress
+	case BuildStateSuccess:
+		run.Status = CheckRunStatusCompleted
+		run.Conclusion = "success"
+	case BuildStateFailure, BuildStateError:
+		run.Status = CheckRunStatusCompleted
+		run.Conclusion = "failure"
+	default:
+		run.Status = CheckRunStatusQueued
+	}
+
+	return run
+}
+
+//IsPublished reports if the commit already has a status of the update context with the same
+//state, description and target URL.
+func (u *StatusUpdate) IsPublished(combined *CombinedStatus) bool {
+	status := u.ToCommitStatus()
+	for _, st := range combined.Statuses {
+		if st.Context == status.Context {
+			//Statuses are listed in reverse chronological order, the first one is the current one
+			return st.State == status.State && st.Description == status.Description && st.TargetURL == status.TargetURL
+		}
+	}
+	return false
+}
+
+//Matches reports if the check run already has the status, conclusion and output of the given one.
+func (r *CheckRun) Matches(other *CheckRun) bool {
+	if r.Status != other.Status || r.Conclusion != other.Conclusion || r.DetailsURL != other.DetailsURL {
+		return false
+	}
+	if r.Output == nil || other.Output == nil {
+		return r.Output == other.Output
+	}
+	return r.Output.Title == other.Output.Title && r.Output.Summary == other.Output.Summary
+}
+
+func (u *StatusUpdate) shortDescription() string {
+	description := []rune(u.Description)
+	if len(description) <= maxStatusDescriptionLength {
+		return u.Description
+	}
+	return string(description[:maxStatusDescriptionLength-3]) + "..."
+}
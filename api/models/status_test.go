@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusUpdate_ToCommitStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		update StatusUpdate
+		want   CommitStatus
+	}{
+		{
+			name:   "test - running is reported as pending",
+			update: StatusUpdate{Context: "ci", State: BuildStateRunning, Description: "The build is running"},
+			want:   CommitStatus{Context: "ci", State: BuildStatePending, Description: "The build is running"},
+		},
+		{
+			name:   "test - long descriptions are truncated",
+			update: StatusUpdate{Context: "ci", State: BuildStateFailure, Description: strings.Repeat("a", 200)},
+			want:   CommitStatus{Context: "ci", State: BuildStateFailure, Description: strings.Repeat("a", 137) + "..."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, &tt.want, tt.update.ToCommitStatus())
+		})
+	}
+}
+
+func TestStatusUpdate_ToCheckRun(t *testing.T) {
+	tests := []struct {
+		state          string
+		wantStatus     string
+		wantConclusion string
+	}{
+		{BuildStatePending, CheckRunStatusQueued, ""},
+		{BuildStateRunning, CheckRunStatusInProgress, ""},
+		{BuildStateSuccess, CheckRunStatusCompleted, "success"},
+		{BuildStateFailure, CheckRunStatusCompleted, "failure"},
+		{BuildStateError, CheckRunStatusCompleted, "failure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			u := &StatusUpdate{Context: "ci", State: tt.state, TargetURL: "http://ci/1"}
+			run := u.ToCheckRun("abc123")
+			assert.Equal(t, "ci", run.Name)
+			assert.Equal(t, "abc123", run.HeadSha)
+			assert.Equal(t, "http://ci/1", run.DetailsURL)
+			assert.Equal(t, tt.wantStatus, run.Status)
+			assert.Equal(t, tt.wantConclusion, run.Conclusion)
+		})
+	}
+}
+
+func TestStatusUpdate_IsPublished(t *testing.T) {
+	var combined CombinedStatus
+	combined.Statuses = append(combined.Statuses, struct {
+		Context     string `json:"context"`
+		State       string `json:"state"`
+		Description string `json:"description"`
+		TargetURL   string `json:"target_url"`
+	}{Context: "ci", State: "pending", Description: "The build is running"})
+
+	tests := []struct {
+		name   string
+		update StatusUpdate
+		want   bool
+	}{
+		{
+			name:   "test - same status",
+			update: StatusUpdate{Context: "ci", State: BuildStateRunning, Description: "The build is running"},
+			want:   true,
+		},
+		{
+			name:   "test - different state",
+			update: StatusUpdate{Context: "ci", State: BuildStateSuccess, Description: "The build is running"},
+			want:   false,
+		},
+		{
+			name:   "test - other context",
+			update: StatusUpdate{Context: "coverage", State: BuildStateRunning, Description: "The build is running"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.update.IsPublished(&combined))
+		})
+	}
+}
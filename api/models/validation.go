@@ -0,0 +1,196 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//Codes of the validation causes
+const (
+	ValidationCodeRequired   = "required"
+	ValidationCodeInvalid    = "invalid"
+	ValidationCodeOutOfRange = "out_of_range"
+	ValidationCodeDuplicated = "duplicated"
+)
+
+//SupportedWorkflowTypes are the workflows a repository can be configured with.
+var SupportedWorkflowTypes = []string{"gitflow"}
+
+var (
+	//Github repository names are up to 100 letters, digits, '.', '-' or '_'
+	repositoryNameRegex = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+
+	//Github logins are up to 39 letters, digits or single hyphens, which can not start or end the login
+	ownerRegex = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)*$`)
+)
+
+//ValidationCause describes why a field of a request payload is invalid.
+type ValidationCause struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+//ValidationError is returned when a request payload has invalid fields, it has a cause per invalid field.
+type ValidationError struct {
+	Causes []ValidationCause
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0)
+	for _, c := range e.Causes {
+		fields = append(fields, c.Field)
+	}
+	return "invalid fields: " + strings.Join(fields, ", ")
+}
+
+//validator collects the causes of the invalid fields of a payload.
+type validator struct {
+	causes []ValidationCause
+}
+
+func (v *validator) add(field string, code string, message string) {
+	v.causes = append(v.causes, ValidationCause{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+//err returns a ValidationError with the collected causes or nil if there is none.
+func (v *validator) err() error {
+	if len(v.causes) == 0 {
+		return nil
+	}
+	return &ValidationError{Causes: v.causes}
+}
+
+func (v *validator) repositoryName(field string, name *string) {
+	switch {
+	case name == nil || *name == "":
+		v.add(field, ValidationCodeRequired, "the repository name is required")
+	case !repositoryNameRegex.MatchString(*name) || *name == "." || *name == "..":
+		v.add(field, ValidationCodeInvalid, "the repository name must have up to 100 letters, digits, '.', '-' or '_'")
+	}
+}
+
+func (v *validator) owner(field string, owner *string) {
+	switch {
+	case owner == nil || *owner == "":
+		v.add(field, ValidationCodeRequired, "the repository owner is required")
+	case len(*owner) > 39 || !ownerRegex.MatchString(*owner):
+		v.add(field, ValidationCodeInvalid, "the repository owner must have up to 39 letters, digits or single hyphens, not starting nor ending with a hyphen")
+	}
+}
+
+func (v *validator) workflowType(field string, wt *string) {
+	if wt == nil || *wt == "" {
+		v.add(field, ValidationCodeRequired, "the workflow type is required")
+		return
+	}
+	for _, t := range SupportedWorkflowTypes {
+		if *wt == t {
+			return
+		}
+	}
+	v.add(field, ValidationCodeInvalid, fmt.Sprintf("the workflow type must be one of: %s", strings.Join(SupportedWorkflowTypes, ", ")))
+}
+
+func (v *validator) percentage(field string, p *float64) {
+	if p != nil && (*p < 0 || *p > 100) {
+		v.add(field, ValidationCodeOutOfRange, "the value must be between 0 and 100")
+	}
+}
+
+func (v *validator) statusChecks(field string, checks []string) {
+	seen := make(map[string]bool)
+	for i, check := range checks {
+		f := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case strings.TrimSpace(check) == "":
+			v.add(f, ValidationCodeRequired, "the status check name is required")
+		case seen[check]:
+			v.add(f, ValidationCodeDuplicated, fmt.Sprintf("the status check %s is duplicated", check))
+		}
+		seen[check] = true
+	}
+}
+
+func (v *validator) environments(field string, envs []EnvironmentPayload) {
+	seen := make(map[string]bool)
+	for i, env := range envs {
+		f := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case env.Name == "":
+			v.add(f+".name", ValidationCodeRequired, "the environment name is required")
+		case seen[env.Name]:
+			v.add(f+".name", ValidationCodeDuplicated, fmt.Sprintf("the environment %s is duplicated", env.Name))
+		}
+		seen[env.Name] = true
+
+		if env.RequiredApprovals < 0 || env.RequiredApprovals > len(env.Approvers) {
+			v.add(f+".required_approvals", ValidationCodeOutOfRange, "the required approvals must be between 0 and the number of approvers")
+		}
+
+		switch env.Target.Type {
+		case "", EnvironmentTargetGithub:
+		case EnvironmentTargetWebhook:
+			if env.Target.URL == "" {
+				v.add(f+".target.url", ValidationCodeRequired, "the webhook target url is required")
+			}
+		case EnvironmentTargetKubernetes:
+			required := []struct {
+				name  string
+				value string
+			}{
+				{"url", env.Target.URL},
+				{"namespace", env.Target.Namespace},
+				{"deployment", env.Target.Deployment},
+				{"container", env.Target.Container},
+				{"image", env.Target.Image},
+			}
+			for _, r := range required {
+				if r.value == "" {
+					v.add(f+".target."+r.name, ValidationCodeRequired, "the kubernetes target "+r.name+" is required")
+				}
+			}
+		default:
+			v.add(f+".target.type", ValidationCodeInvalid, "the target type must be one of: github, webhook, kubernetes")
+		}
+	}
+}
+
+//Validate checks the fields of a PostRequestPayload.
+//It returns a ValidationError with a cause per invalid field or nil if the payload is valid.
+func (r *PostRequestPayload) Validate() error {
+	var v validator
+
+	v.repositoryName("repository.name", r.Repository.Name)
+	v.owner("repository.owner", r.Repository.Owner)
+	v.statusChecks("repository.required_status_checks", r.Repository.RequireStatusChecks)
+	v.workflowType("workflow.type", r.Workflow.Type)
+
+	if r.CodeCoverage.PullRequestThreshold == nil {
+		v.add("code_coverage.pull_request_threshold", ValidationCodeRequired, "the pull request coverage threshold is required")
+	}
+	v.percentage("code_coverage.pull_request_threshold", r.CodeCoverage.PullRequestThreshold)
+	v.percentage("code_coverage.max_decrease", r.CodeCoverage.MaxDecrease)
+
+	v.environments("deployment.environments", r.Deployment.Environments)
+
+	return v.err()
+}
+
+//Validate checks the fields of a PutRequestPayload, all of them are optional.
+//It returns a ValidationError with a cause per invalid field or nil if the payload is valid.
+func (r *PutRequestPayload) Validate() error {
+	var v validator
+
+	v.statusChecks("repository.required_status_checks", r.Repository.RequireStatusChecks)
+	v.percentage("code_coverage.pull_request_threshold", r.CodeCoverage.PullRequestThreshold)
+	v.percentage("code_coverage.max_decrease", r.CodeCoverage.MaxDecrease)
+	v.environments("deployment.environments", r.Deployment.Environments)
+
+	return v.err()
+}
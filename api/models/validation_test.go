@@ -0,0 +1,149 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/herbal828/ci_cd-api/api/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func validPostRequestPayload() *PostRequestPayload {
+	threshold := 80.0
+
+	var r PostRequestPayload
+	r.Repository.Name = utils.Stringify("api")
+	r.Repository.Owner = utils.Stringify("acme")
+	r.Repository.RequireStatusChecks = []string{"ci", "coverage"}
+	r.Workflow.Type = utils.Stringify("gitflow")
+	r.CodeCoverage.PullRequestThreshold = &threshold
+	return &r
+}
+
+func TestPostRequestPayload_Validate(t *testing.T) {
+	outOfRange := 101.0
+	negative := -1.0
+
+	tests := []struct {
+		name       string
+		modify     func(r *PostRequestPayload)
+		wantFields []string
+		wantCodes  []string
+	}{
+		{
+			name:   "test - valid payload",
+			modify: func(r *PostRequestPayload) {},
+		},
+		{
+			name: "test - missing required fields",
+			modify: func(r *PostRequestPayload) {
+				*r = PostRequestPayload{}
+			},
+			wantFields: []string{"repository.name", "repository.owner", "workflow.type", "code_coverage.pull_request_threshold"},
+			wantCodes:  []string{ValidationCodeRequired, ValidationCodeRequired, ValidationCodeRequired, ValidationCodeRequired},
+		},
+		{
+			name: "test - invalid github names",
+			modify: func(r *PostRequestPayload) {
+				r.Repository.Name = utils.Stringify("my api")
+				r.Repository.Owner = utils.Stringify("-acme")
+			},
+			wantFields: []string{"repository.name", "repository.owner"},
+			wantCodes:  []string{ValidationCodeInvalid, ValidationCodeInvalid},
+		},
+		{
+			name: "test - owner with consecutive hyphens",
+			modify: func(r *PostRequestPayload) {
+				r.Repository.Owner = utils.Stringify("acme--labs")
+			},
+			wantFields: []string{"repository.owner"},
+			wantCodes:  []string{ValidationCodeInvalid},
+		},
+		{
+			name: "test - unsupported workflow",
+			modify: func(r *PostRequestPayload) {
+				r.Workflow.Type = utils.Stringify("trunk_based")
+			},
+			wantFields: []string{"workflow.type"},
+			wantCodes:  []string{ValidationCodeInvalid},
+		},
+		{
+			name: "test - thresholds out of range",
+			modify: func(r *PostRequestPayload) {
+				r.CodeCoverage.PullRequestThreshold = &outOfRange
+				r.CodeCoverage.MaxDecrease = &negative
+			},
+			wantFields: []string{"code_coverage.pull_request_threshold", "code_coverage.max_decrease"},
+			wantCodes:  []string{ValidationCodeOutOfRange, ValidationCodeOutOfRange},
+		},
+		{
+			name: "test - duplicated and empty status checks",
+			modify: func(r *PostRequestPayload) {
+				r.Repository.RequireStatusChecks = []string{"ci", "", "ci"}
+			},
+			wantFields: []string{"repository.required_status_checks[1]", "repository.required_status_checks[2]"},
+			wantCodes:  []string{ValidationCodeRequired, ValidationCodeDuplicated},
+		},
+		{
+			name: "test - invalid environments",
+			modify: func(r *PostRequestPayload) {
+				var production EnvironmentPayload
+				production.Name = "production"
+				production.RequiredApprovals = 1
+				production.Target.Type = EnvironmentTargetWebhook
+
+				var staging EnvironmentPayload
+				staging.Name = "production"
+				staging.Target.Type = "ftp"
+
+				r.Deployment.Environments = []EnvironmentPayload{production, staging}
+			},
+			wantFields: []string{
+				"deployment.environments[0].required_approvals",
+				"deployment.environments[0].target.url",
+				"deployment.environments[1].name",
+				"deployment.environments[1].target.type",
+			},
+			wantCodes: []string{ValidationCodeOutOfRange, ValidationCodeRequired, ValidationCodeDuplicated, ValidationCodeInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validPostRequestPayload()
+			tt.modify(r)
+
+			err := r.Validate()
+
+			if tt.wantFields == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			validationErr, ok := err.(*ValidationError)
+			assert.True(t, ok)
+
+			var fields, codes []string
+			for _, c := range validationErr.Causes {
+				fields = append(fields, c.Field)
+				codes = append(codes, c.Code)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
+
+func TestPutRequestPayload_Validate(t *testing.T) {
+	outOfRange := 150.0
+
+	var valid PutRequestPayload
+	assert.Nil(t, valid.Validate())
+
+	var invalid PutRequestPayload
+	invalid.CodeCoverage.PullRequestThreshold = &outOfRange
+	invalid.Repository.RequireStatusChecks = []string{"ci", "ci"}
+
+	assert.Equal(t, &ValidationError{Causes: []ValidationCause{
+		{Field: "repository.required_status_checks[1]", Code: ValidationCodeDuplicated, Message: "the status check ci is duplicated"},
+		{Field: "code_coverage.pull_request_threshold", Code: ValidationCodeOutOfRange, Message: "the value must be between 0 and 100"},
+	}}, invalid.Validate())
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//Semantic version bumps
+const (
+	BumpMajor = "major"
+	BumpMinor = "minor"
+	BumpPatch = "patch"
+)
+
+//Version represents a semantic version (major.minor.patch).
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+//ParseVersion converts a string like 1.2.3 or v1.2.3 into a Version.
+func ParseVersion(s string) (*Version, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+
+	if len(parts) != 3 {
+		return nil, errors.New(fmt.Sprintf("invalid semantic version %s", s))
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, errors.New(fmt.Sprintf("invalid semantic version %s", s))
+		}
+		numbers[i] = n
+	}
+
+	return &Version{
+		Major: numbers[0],
+		Minor: numbers[1],
+		Patch: numbers[2],
+	}, nil
+}
+
+//String returns the version formatted as major.minor.patch
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+//Bump returns the next version for the given kind of change (major, minor or patch).
+func (v Version) Bump(kind string) (*Version, error) {
+	switch kind {
+	case BumpMajor:
+		return &Version{Major: v.Major + 1}, nil
+	case BumpMinor:
+		return &Version{Major: v.Major, Minor: v.Minor + 1}, nil
+	case BumpPatch:
+		return &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("invalid version bump %s", kind))
+	}
+}
+
+//LessThan reports if the version precedes the given one.
+func (v Version) LessThan(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	return v.Patch < o.Patch
+}
+
+//GetLatestVersionTag returns the tag with the greatest semantic version and its version.
+//Tags which are not semantic versions are ignored. Returns nil if there is no version tag.
+func GetLatestVersionTag(tags []Tag) (*Tag, *Version) {
+	var latestTag *Tag
+	var latest *Version
+
+	for i := range tags {
+		v, err := ParseVersion(tags[i].Name)
+		if err != nil {
+			continue
+		}
+		if latest == nil || latest.LessThan(*v) {
+			latestTag = &tags[i]
+			latest = v
+		}
+	}
+
+	return latestTag, latest
+}
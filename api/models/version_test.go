@@ -0,0 +1,97 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    *Version
+		wantErr bool
+	}{
+		{
+			name: "plain version",
+			arg:  "1.2.3",
+			want: &Version{Major: 1, Minor: 2, Patch: 3},
+		},
+		{
+			name: "tag version",
+			arg:  "v10.0.1",
+			want: &Version{Major: 10, Minor: 0, Patch: 1},
+		},
+		{
+			name:    "incomplete version",
+			arg:     "1.2",
+			wantErr: true,
+		},
+		{
+			name:    "non numeric version",
+			arg:     "1.2.x",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersion(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_Bump(t *testing.T) {
+	v := Version{Major: 1, Minor: 4, Patch: 2}
+	tests := []struct {
+		name    string
+		kind    string
+		want    string
+		wantErr bool
+	}{
+		{name: "major", kind: BumpMajor, want: "2.0.0"},
+		{name: "minor", kind: BumpMinor, want: "1.5.0"},
+		{name: "patch", kind: BumpPatch, want: "1.4.3"},
+		{name: "invalid", kind: "mayor", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Bump(tt.kind)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Version.Bump() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("Version.Bump() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_LessThan(t *testing.T) {
+	if !(Version{1, 9, 9}).LessThan(Version{1, 10, 0}) {
+		t.Errorf("Version.LessThan() 1.9.9 < 1.10.0 should be true")
+	}
+	if (Version{2, 0, 0}).LessThan(Version{1, 10, 0}) {
+		t.Errorf("Version.LessThan() 2.0.0 < 1.10.0 should be false")
+	}
+}
+
+func TestGetLatestVersionTag(t *testing.T) {
+	tags := []Tag{{Name: "v1.2.0"}, {Name: "latest"}, {Name: "v1.10.1"}, {Name: "v1.9.0"}}
+
+	tag, version := GetLatestVersionTag(tags)
+	if tag == nil || tag.Name != "v1.10.1" || version.String() != "1.10.1" {
+		t.Errorf("GetLatestVersionTag() = %v, %v, want v1.10.1", tag, version)
+	}
+
+	if tag, version := GetLatestVersionTag([]Tag{{Name: "latest"}}); tag != nil || version != nil {
+		t.Errorf("GetLatestVersionTag() = %v, %v, want nil", tag, version)
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import "strings"
+
+//GithubWebhookPayload represents the fields this API uses from the events delivered by the repository webhook.
+type GithubWebhookPayload struct {
+	Ref        string `json:"ref"`
+	RefType    string `json:"ref_type"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+		Owner    struct {
+			Login string `json:"login"`
+			Name  string `json:"name"`
+		} `json:"owner"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+//GetBranchName returns the branch referenced by a push or create event.
+//The second value is false when the event does not reference a branch (tags, deletions, etc).
+func (p *GithubWebhookPayload) GetBranchName() (string, bool) {
+	if p.Deleted {
+		return "", false
+	}
+
+	//Create events send the bare name of the ref and its type
+	if p.RefType != "" {
+		return p.Ref, p.RefType == "branch"
+	}
+
+	//Push events send the full reference name
+	if strings.HasPrefix(p.Ref, "refs/heads/") {
+		return strings.TrimPrefix(p.Ref, "refs/heads/"), true
+	}
+
+	return "", false
+}
+
+//GithubDeploymentStatusPayload represents the fields this API uses from the deployment_status events.
+type GithubDeploymentStatusPayload struct {
+	DeploymentStatus struct {
+		State       string `json:"state"`
+		Description string `json:"description"`
+		LogURL      string `json:"log_url"`
+		TargetURL   string `json:"target_url"`
+	} `json:"deployment_status"`
+	Deployment struct {
+		ID int64 `json:"id"`
+	} `json:"deployment"`
+	Repository struct {
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
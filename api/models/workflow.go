@@ -1,5 +1,11 @@
 package models
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 type WorkflowConfig struct {
 	Name          string `json:"name"`
 	Description   Description
@@ -17,6 +23,7 @@ type Branch struct {
 	Name         string `json:"name"`
 	Releasable   bool   `json:"releaseable"`
 	StartWith    bool   `json:"start_with"`
+	Pattern      string `json:"pattern"`
 }
 
 type Requirements struct {
@@ -36,3 +43,47 @@ type RequiredStatusChecks struct {
 	IncludeAdmins bool     `json:"include_admins"`
 	Strict        bool     `json:"strict"`
 }
+
+//Matches reports if a branch name follows the naming convention of the workflow branch.
+//Stable branches must match the exact name while the branches flagged with StartWith
+//must follow the Pattern, where '*' stands for any name and 'x.y.z' for a semantic version.
+func (b *Branch) Matches(name string) bool {
+	if !b.StartWith {
+		return name == b.Name
+	}
+
+	pattern := b.Pattern
+	if pattern == "" {
+		pattern = b.Name + "/*"
+	}
+
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.Replace(expr, `\*`, `.+`, -1)
+	expr = strings.Replace(expr, `x\.y\.z`, `\d+\.\d+\.\d+`, -1)
+
+	matched, err := regexp.MatchString("^"+expr+"$", name)
+	if err != nil {
+		return false
+	}
+
+	return matched
+}
+
+//CheckBranchName validates a branch name against the branches declared by the workflow.
+//Returns an empty reason when the name is allowed.
+func (wf *WorkflowConfig) CheckBranchName(name string) (bool, string) {
+	var allowed []string
+
+	for _, branch := range wf.Description.Branches {
+		if branch.Matches(name) {
+			return true, ""
+		}
+		if branch.StartWith && branch.Pattern != "" {
+			allowed = append(allowed, branch.Pattern)
+		} else {
+			allowed = append(allowed, branch.Name)
+		}
+	}
+
+	return false, fmt.Sprintf("branch %s does not follow the %s naming convention (allowed: %s)", name, wf.Name, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,90 @@
+package models
+
+import "testing"
+
+func TestBranch_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch Branch
+		arg    string
+		want   bool
+	}{
+		{
+			name:   "stable branch with the exact name",
+			branch: Branch{Name: "develop", Stable: true},
+			arg:    "develop",
+			want:   true,
+		},
+		{
+			name:   "stable branch with a different name",
+			branch: Branch{Name: "develop", Stable: true},
+			arg:    "develop/new",
+			want:   false,
+		},
+		{
+			name:   "feature branch with a name",
+			branch: Branch{Name: "feature", StartWith: true, Pattern: "feature/*"},
+			arg:    "feature/new-login",
+			want:   true,
+		},
+		{
+			name:   "feature branch without a name",
+			branch: Branch{Name: "feature", StartWith: true, Pattern: "feature/*"},
+			arg:    "feature/",
+			want:   false,
+		},
+		{
+			name:   "start with branch without pattern",
+			branch: Branch{Name: "bugfix", StartWith: true},
+			arg:    "bugfix/npe",
+			want:   true,
+		},
+		{
+			name:   "release branch with a semantic version",
+			branch: Branch{Name: "release", StartWith: true, Pattern: "release/x.y.z"},
+			arg:    "release/1.12.0",
+			want:   true,
+		},
+		{
+			name:   "release branch without a semantic version",
+			branch: Branch{Name: "release", StartWith: true, Pattern: "release/x.y.z"},
+			arg:    "release/next",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.branch.Matches(tt.arg); got != tt.want {
+				t.Errorf("Branch.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowConfig_CheckBranchName(t *testing.T) {
+	wf := WorkflowConfig{
+		Name: "gitflow",
+		Description: Description{
+			Branches: []Branch{
+				{Name: "master", Stable: true},
+				{Name: "develop", Stable: true},
+				{Name: "feature", StartWith: true, Pattern: "feature/*"},
+				{Name: "hotfix", StartWith: true, Pattern: "hotfix/x.y.z"},
+			},
+		},
+	}
+
+	if allowed, reason := wf.CheckBranchName("hotfix/1.0.1"); !allowed || reason != "" {
+		t.Errorf("WorkflowConfig.CheckBranchName() = %v, %s, want true", allowed, reason)
+	}
+
+	allowed, reason := wf.CheckBranchName("my-branch")
+	if allowed {
+		t.Errorf("WorkflowConfig.CheckBranchName() = %v, want false", allowed)
+	}
+
+	want := "branch my-branch does not follow the gitflow naming convention (allowed: master, develop, feature/*, hotfix/x.y.z)"
+	if reason != want {
+		t.Errorf("WorkflowConfig.CheckBranchName() reason = %s, want %s", reason, want)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+var (
+	//ErrInvalidApproval is returned when an approval has no user or an unknown decision, or a rejection has no reason.
+	ErrInvalidApproval = errors.New("invalid approval")
+
+	//ErrApproverNotAuthorized is returned when the user is not an approver of the deployment environment.
+	ErrApproverNotAuthorized = errors.New("user is not authorized to approve deployments of the environment")
+
+	//ErrDeploymentNotAwaitingApproval is returned when a deployment is reviewed after its approval gate.
+	ErrDeploymentNotAwaitingApproval = errors.New("the deployment is not awaiting approval")
+
+	//ErrAlreadyApproved is returned when a user approves a deployment twice while the first approval is valid.
+	ErrAlreadyApproved = errors.New("the user already approved the deployment")
+
+	//ErrApproverNotAuthenticated is returned when a deployment is reviewed without a valid Github token.
+	ErrApproverNotAuthenticated = errors.New("a valid github token is required to review deployments")
+)
+
+//Review records the decision of an environment approver about a deployment awaiting approval.
+//The approver is the owner of the given Github token.
+//A rejection finishes the deployment. Once the deployment has as many valid approvals as the environment
+//requires, it is created on Github and moves to pending.
+func (s *Deployment) Review(id uint64, token string, r *models.PostApprovalRequestPayload) (*models.Deployment, error) {
+
+	if r.Decision != models.ApprovalDecisionApprove && r.Decision != models.ApprovalDecisionReject {
+		return nil, ErrInvalidApproval
+	}
+
+	if r.Decision == models.ApprovalDecisionReject && r.Reason == "" {
+		return nil, ErrInvalidApproval
+	}
+
+	user, err := s.authenticate(token)
+
+	if err != nil {
+		return nil, err
+	}
+	r.User = user
+
+	deployment, err := s.Get(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var config models.Configuration
+	if err := s.SQL.GetBy(&config, "id = ?", *deployment.ConfigurationID); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	env := config.GetEnvironment(deployment.Environment)
+	if env == nil {
+		return nil, ErrEnvironmentNotFound
+	}
+
+	if !env.IsApprover(r.User) {
+		return nil, ErrApproverNotAuthorized
+	}
+
+	if deployment.State != models.DeploymentStateAwaitingApproval {
+		return nil, ErrDeploymentNotAwaitingApproval
+	}
+
+	now := time.Now().UTC()
+	if r.Decision == models.ApprovalDecisionApprove && deployment.HasValidApproval(r.User, now) {
+		return nil, ErrAlreadyApproved
+	}
+
+	approval := models.NewApproval(deployment, env, r)
+	deployment.Approvals = append(deployment.Approvals, *approval)
+
+	approved := false
+	switch {
+	case r.Decision == models.ApprovalDecisionReject:
+		deployment.AddStatus(models.DeploymentStateRejected, fmt.Sprintf("Rejected by %s: %s", r.User, r.Reason), "")
+	case deployment.CountValidApprovals(now) >= env.RequiredApprovals:
+		if err := s.start(&config, deployment); err != nil {
+			return nil, err
+		}
+		approved = true
+	}
+
+	if err := s.SQL.Update(deployment); err != nil {
+		return nil, errors.New("error updating deployment")
+	}
+
+	if approved {
+		if err := s.deliver(&config, env, deployment); err != nil {
+			return nil, err
+		}
+	}
+
+	return deployment, nil
+}
+
+//authenticate returns the login of the Github user the token belongs to.
+func (s *Deployment) authenticate(token string) (string, error) {
+	if token == "" {
+		return "", ErrApproverNotAuthenticated
+	}
+
+	user, err := s.NewUserGithubClient(token).GetAuthenticatedUser()
+
+	if err != nil {
+		if err == clients.ErrInvalidToken {
+			return "", ErrApproverNotAuthenticated
+		}
+		return "", err
+	}
+
+	if user.Login == "" {
+		return "", ErrApproverNotAuthenticated
+	}
+
+	return user.Login, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+)
+
+//BranchPolicyService is an interface which represents the BranchPolicyService for testing purpose.
+type BranchPolicyService interface {
+	CheckBranch(event string, payload *models.GithubWebhookPayload) (*models.BranchViolation, error)
+	GetViolations(repoName string) ([]models.BranchViolation, error)
+}
+
+//BranchPolicy represents the BranchPolicyService layer
+//It has an instance of a DBClient layer
+type BranchPolicy struct {
+	SQL storage.SQLStorage
+}
+
+//NewBranchPolicyService initializes a BranchPolicyService
+func NewBranchPolicyService(sql storage.SQLStorage) *BranchPolicy {
+	return &BranchPolicy{
+		SQL: sql,
+	}
+}
+
+//CheckBranch validates the branch referenced by a push or create event against the repository workflow.
+//Returns the violation registered for the branch or nil if the branch follows the naming conventions.
+func (s *BranchPolicy) CheckBranch(event string, payload *models.GithubWebhookPayload) (*models.BranchViolation, error) {
+
+	branchName, isBranch := payload.GetBranchName()
+
+	//Only branches are subject to the naming policy
+	if !isBranch {
+		return nil, nil
+	}
+
+	var config models.Configuration
+	if err := s.SQL.GetBy(&config, "id = ?", payload.Repository.FullName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	wfc := configs.GetWorkflowConfiguration(&config)
+
+	allowed, reason := wfc.CheckBranchName(branchName)
+
+	if allowed {
+		return nil, nil
+	}
+
+	//The branch could be already reported by a previous event
+	var violation models.BranchViolation
+	if err := s.SQL.GetBy(&violation, "configuration_id = ? AND branch = ?", *config.ID, branchName); err == nil {
+		return &violation, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, errors.New("error checking branch violation existence")
+	}
+
+	violation = models.BranchViolation{
+		ConfigurationID: config.ID,
+		Branch:          branchName,
+		Event:           event,
+		Sender:          payload.Sender.Login,
+		Reason:          reason,
+	}
+
+	if err := s.SQL.Insert(&violation); err != nil {
+		return nil, errors.New("error saving branch violation")
+	}
+
+	return &violation, nil
+}
+
+//GetViolations returns all the branches which do not follow the naming conventions of the repository workflow.
+//Returns an error if the config is not found.
+func (s *BranchPolicy) GetViolations(repoName string) ([]models.BranchViolation, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	violations := make([]models.BranchViolation, 0)
+	if err := s.SQL.GetBy(&violations, "configuration_id = ?", *config.ID); err != nil {
+		return nil, errors.New("error getting branch violations")
+	}
+
+	return violations, nil
+}
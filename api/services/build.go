@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+	"log"
+)
+
+//ErrInvalidBuildRequest is returned when a build is requested without a branch or a commit.
+var ErrInvalidBuildRequest = errors.New("invalid build request")
+
+//BuildService is an interface which represents the BuildService for testing purpose.
+type BuildService interface {
+	Create(repoName string, r *models.PostBuildRequestPayload) (*models.Build, error)
+	Get(repoName string, id uint64) (*models.Build, error)
+}
+
+//Build represents the BuildService layer
+//It has an instance of a DBClient layer,
+//A CI builder client instance and a StatusPublisher instance
+type Build struct {
+	SQL             storage.SQLStorage
+	BuilderClient   clients.CIBuilderClient
+	StatusPublisher StatusPublisher
+}
+
+//NewBuildService initializes a BuildService
+func NewBuildService(sql storage.SQLStorage) *Build {
+	return &Build{
+		SQL:             sql,
+		BuilderClient:   clients.NewCIBuilderClient(),
+		StatusPublisher: NewStatusPublisher(),
+	}
+}
+
+//Create triggers a build of a repository branch at the given commit on the CI backend,
+//records it into database and reports it as pending on the commit.
+func (s *Build) Create(repoName string, r *models.PostBuildRequestPayload) (*models.Build, error) {
+
+	if r.Branch == "" || r.Sha == "" {
+		return nil, ErrInvalidBuildRequest
+	}
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	status, err := s.BuilderClient.TriggerBuild(&config, r.Branch, r.Sha)
+
+	if err != nil {
+		return nil, err
+	}
+
+	build := models.NewBuild(&config, r, status)
+
+	if err := s.SQL.Insert(build); err != nil {
+		return nil, errors.New("error saving build")
+	}
+
+	//The build is already running, a failed report must not make the client trigger it again
+	if err := s.reportStatus(&config, build); err != nil {
+		log.Printf("error reporting build %d status of %s: %v", *build.ID, *config.ID, err)
+	}
+
+	return build, nil
+}
+
+//Get returns a build of the repository.
+//While the build is not finished, its status is refreshed from the CI backend and any
+//change of state is recorded and reported on the commit.
+func (s *Build) Get(repoName string, id uint64) (*models.Build, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	var build models.Build
+	if err := s.SQL.GetBy(&build, "configuration_id = ? AND id = ?", *config.ID, id); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error getting build")
+		}
+		return nil, err
+	}
+
+	if build.IsFinished() {
+		return &build, nil
+	}
+
+	status, err := s.BuilderClient.GetBuildStatus(&config, build.ExternalID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !build.SetStatus(status) {
+		return &build, nil
+	}
+
+	if err := s.SQL.Update(&build); err != nil {
+		return nil, errors.New("error updating build")
+	}
+
+	if err := s.reportStatus(&config, &build); err != nil {
+		log.Printf("error reporting build %d status of %s: %v", *build.ID, *config.ID, err)
+	}
+
+	return &build, nil
+}
+
+//reportStatus reports the build state as the continuous integration commit status.
+//The build only stands for the CI check, the other required checks are reported by their own tools.
+func (s *Build) reportStatus(config *models.Configuration, build *models.Build) error {
+	update := models.StatusUpdate{
+		Context:     models.BuildStatusContext,
+		State:       build.State,
+		Description: build.Description(),
+		TargetURL:   build.LogURL,
+	}
+
+	return s.StatusPublisher.PublishStatus(config, build.Sha, &update)
+}
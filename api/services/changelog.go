@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+)
+
+//ChangelogService is an interface which represents the ChangelogService for testing purpose.
+type ChangelogService interface {
+	GetReleaseChangelog(repoName string, version string) (*models.Changelog, error)
+	Generate(config *models.Configuration, release *models.Release) (*models.Changelog, error)
+}
+
+//Changelog represents the ChangelogService layer
+//It has an instance of a DBClient layer and
+//A github client instance
+type Changelog struct {
+	SQL          storage.SQLStorage
+	GithubClient clients.GithubClient
+}
+
+//NewChangelogService initializes a ChangelogService
+func NewChangelogService(sql storage.SQLStorage) *Changelog {
+	return &Changelog{
+		SQL:          sql,
+		GithubClient: clients.NewGithubClient(),
+	}
+}
+
+//GetReleaseChangelog builds the changelog of a recorded release.
+//Returns an error if the configuration or the release are not found.
+func (s *Changelog) GetReleaseChangelog(repoName string, version string) (*models.Changelog, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	var release models.Release
+	if err := s.SQL.GetBy(&release, "configuration_id = ? AND version = ?", *config.ID, version); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking release existence")
+		}
+		return nil, err
+	}
+
+	return s.Generate(&config, &release)
+}
+
+//Generate builds the changelog of a release.
+//It collects the pull requests and commits between the previous version tag and the release, which is
+//its tag once the release is tagged or its branch otherwise.
+func (s *Changelog) Generate(config *models.Configuration, release *models.Release) (*models.Changelog, error) {
+
+	version, err := models.ParseVersion(release.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	to := release.Branch
+	if release.TagSha != "" {
+		to = release.Tag()
+	}
+
+	tags, listTagsErr := s.GithubClient.ListTags(config)
+
+	if listTagsErr != nil {
+		return nil, listTagsErr
+	}
+
+	//The previous version is the greatest tag lower than the release one
+	var previousTags []models.Tag
+	for _, t := range tags {
+		if v, err := models.ParseVersion(t.Name); err == nil && v.LessThan(*version) {
+			previousTags = append(previousTags, t)
+		}
+	}
+
+	var commits []models.Commit
+	from := ""
+
+	if previousTag, _ := models.GetLatestVersionTag(previousTags); previousTag != nil {
+		compare, compareErr := s.GithubClient.CompareCommits(config, previousTag.Name, to)
+
+		if compareErr != nil {
+			return nil, compareErr
+		}
+
+		commits = compare.Commits
+		from = previousTag.Name
+	} else {
+		branchCommits, listCommitsErr := s.GithubClient.ListCommits(config, to)
+
+		if listCommitsErr != nil {
+			return nil, listCommitsErr
+		}
+
+		commits = branchCommits
+		from = "the beginning"
+	}
+
+	merged, direct := models.SplitCommits(commits)
+
+	prs := make(map[int]*models.PullRequest)
+	for _, m := range merged {
+		pr, getPrErr := s.GithubClient.GetPullRequest(config, m.Number)
+
+		//The merge commit message is enough to describe a pull request which can not be found
+		if getPrErr != nil {
+			if getPrErr == clients.ErrPullRequestNotFound {
+				continue
+			}
+			return nil, getPrErr
+		}
+
+		prs[m.Number] = pr
+	}
+
+	return models.NewChangelog(release.Version, from, to, merged, direct, prs), nil
+}
@@ -1,40 +1,65 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/herbal828/ci_cd-api/api/clients"
 	"github.com/herbal828/ci_cd-api/api/models"
 	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/jsonpatch"
 	"github.com/jinzhu/gorm"
+	"log"
+	"reflect"
+	"strings"
+)
+
+//ErrConfigurationAlreadyExists is returned when a repository which is already configured is created again without upsert.
+var ErrConfigurationAlreadyExists = errors.New("the repository is already configured")
+
+var (
+	//ErrPreconditionRequired is returned when a configuration is changed without the version the client saw.
+	ErrPreconditionRequired = errors.New("the If-Match header is required")
+
+	//ErrPreconditionFailed is returned when a configuration is changed from a version which is not the current one.
+	ErrPreconditionFailed = errors.New("the configuration was modified by another request")
+
+	//ErrUnsupportedPatchType is returned when a partial update is neither a JSON Merge Patch nor a JSON Patch.
+	ErrUnsupportedPatchType = errors.New("unsupported patch media type")
 )
 
 //ConfigurationService is an interface which represents the ConfigurationService for testing purpose.
 type ConfigurationService interface {
-	Create(*models.PostRequestPayload) (*models.Configuration, error)
+	Create(r *models.PostRequestPayload, upsert bool) (*models.Configuration, error)
 	Get(string) (*models.Configuration, error)
-	Update(r *models.PutRequestPayload) (*models.Configuration, error)
-	Delete(id string) error
+	List(q *models.ConfigurationListQuery) ([]models.Configuration, string, error)
+	Update(r *models.PutRequestPayload, ifMatch string) (*models.Configuration, error)
+	Patch(id string, mediaType string, patch []byte, ifMatch string) (*models.Configuration, error)
+	Delete(id string, ifMatch string) error
 }
 
 //Configuration represents the ConfigurationService layer
 //It has an instance of a DBClient layer and
 //A github client instance
 type Configuration struct {
-	SQL          storage.SQLStorage
-	GithubClient clients.GithubClient
+	SQL           storage.SQLStorage
+	GithubClient  clients.GithubClient
+	BuilderClient clients.CIBuilderClient
 }
 
 //NewConfigurationService initializes a ConfigurationService
 func NewConfigurationService(sql storage.SQLStorage) *Configuration {
 	return &Configuration{
-		SQL:          sql,
-		GithubClient: clients.NewGithubClient(),
+		SQL:           sql,
+		GithubClient:  clients.NewGithubClient(),
+		BuilderClient: clients.NewCIBuilderClient(),
 	}
 }
 
 //Create creates a Release Process valid configuration.
 //It performs all the actions needed to enabled successfuly Release Process.
-func (s *Configuration) Create(r *models.PostRequestPayload) (*models.Configuration, error) {
+//If the repository is already configured, it returns the existing configuration along with ErrConfigurationAlreadyExists,
+//unless upsert is requested, then the differences of the payload are applied to it.
+func (s *Configuration) Create(r *models.PostRequestPayload, upsert bool) (*models.Configuration, error) {
 
 	config := *models.NewConfiguration(r)
 
@@ -55,22 +80,64 @@ func (s *Configuration) Create(r *models.PostRequestPayload) (*models.Configurat
 			return nil, setWorkflowError
 		}
 
+		//Register the webhook which reports the repository events
+		if setWebhookError := s.SetWebhook(&config); setWebhookError != nil {
+			return nil, setWebhookError
+		}
+
+		//Provision the continuous integration job
+		if createJobError := s.BuilderClient.CreateJob(&config); createJobError != nil {
+			s.rollbackCreate(&config, false)
+			return nil, createJobError
+		}
+
 		//Save it into database
 		if err := s.SQL.Insert(&config); err != nil {
+			s.rollbackCreate(&config, true)
 			return nil, errors.New("error saving new configuration")
 		}
 		return &config, nil
 
-	} else { //If configuration already exists then return it
-		return &cf, nil
+	} else if !upsert { //If configuration already exists then it is a conflict
+		return &cf, ErrConfigurationAlreadyExists
 	}
+
+	return s.upsert(&cf, r)
+}
+
+//upsert applies the differences of a PostRequestPayload to an existing configuration.
+//The workflow is set again only if the workflow type or the required status checks changed.
+func (s *Configuration) upsert(oldConfig *models.Configuration, r *models.PostRequestPayload) (*models.Configuration, error) {
+
+	checksChanged := r.Repository.RequireStatusChecks != nil && !models.EqualChecks(oldConfig.GetRequiredStatusCheck(), r.Repository.RequireStatusChecks)
+
+	newConfig := *oldConfig
+	newConfig.Version++
+	workflowChanged := newConfig.UpsertConfiguration(r)
+
+	if workflowChanged {
+		if setWorkflowError := s.SetWorkflow(&newConfig); setWorkflowError != nil {
+			return nil, setWorkflowError
+		}
+	}
+
+	//Repair the repository webhook in case it was removed from Github
+	if setWebhookError := s.SetWebhook(&newConfig); setWebhookError != nil {
+		return nil, setWebhookError
+	}
+
+	//The status checks and the environments are replaced in their child tables
+	if err := s.saveVersion(&newConfig, oldConfig.Version, checksChanged, r.Deployment.Environments != nil); err != nil {
+		return nil, err
+	}
+	return &newConfig, nil
 }
 
 //Get searches a configuration into database.
 //Returns an error if the config is not found.
 func (s *Configuration) Get(id string) (*models.Configuration, error) {
 	var cf models.Configuration
-	if err := s.SQL.GetBy(&cf, "id = ?", id); err != nil {
+	if err := getConfiguration(s.SQL, &cf, id); err != nil {
 		if err != gorm.ErrRecordNotFound {
 			return nil, errors.New("error checking configuration existance")
 		}
@@ -79,10 +146,28 @@ func (s *Configuration) Get(id string) (*models.Configuration, error) {
 	return &cf, nil
 }
 
+//List returns a page of the configurations which match the query filters and the cursor of the next page.
+//The cursor is empty when it is the last page.
+func (s *Configuration) List(q *models.ConfigurationListQuery) ([]models.Configuration, string, error) {
+	var configs []models.Configuration
+
+	//One more configuration is requested to know if there is a next page
+	if err := s.SQL.GetPage(&configs, q.Order(), q.Limit+1, q.Where()...); err != nil {
+		return nil, "", errors.New("error listing configurations")
+	}
+
+	if len(configs) <= q.Limit {
+		return configs, "", nil
+	}
+
+	configs = configs[:q.Limit]
+	return configs, q.NextCursor(&configs[q.Limit-1]), nil
+}
+
 //Update modifies a configuration.
-//It receives a PutRequestPayload.
-//Returns an error if the config is not found or if it some problem updating the config.
-func (s *Configuration) Update(r *models.PutRequestPayload) (*models.Configuration, error) {
+//It receives a PutRequestPayload and the If-Match header with the version of the configuration the client saw.
+//Returns an error if the config is not found, if it is not the current version or if it some problem updating the config.
+func (s *Configuration) Update(r *models.PutRequestPayload, ifMatch string) (*models.Configuration, error) {
 
 	oldConfig, err := s.Get(*r.Repository.Name)
 
@@ -90,35 +175,98 @@ func (s *Configuration) Update(r *models.PutRequestPayload) (*models.Configurati
 		return nil, err
 	}
 
+	if err := s.checkVersion(oldConfig, ifMatch); err != nil {
+		return nil, err
+	}
+
 	newConfig := *oldConfig
+	newConfig.Version++
 	newConfig.UpdateConfiguration(r)
 
-	//Update the repository status checks
-	if r.Repository.RequireStatusChecks != nil {
-		//TODO: Cambiar la proteccion con los nuevos status
+	//Repair the repository webhook in case it was removed from Github
+	if setWebhookError := s.SetWebhook(&newConfig); setWebhookError != nil {
+		return nil, setWebhookError
+	}
 
-		//TODO: Change this, because it is a change made in order to be able to update the required status checks
-		//we did this because when we updated the fields, it doesn't update them in the require_status_check
-		// child table, so we removed them and then saved the new ones.
+	//TODO: Cambiar la proteccion con los nuevos status
 
-		//Delete from configurations DB
-		if sqlErr := s.SQL.DeleteFromRequireStatusChecksByConfigurationID(oldConfig.ID); sqlErr != nil {
-			return nil, sqlErr
-		}
+	//Save the new config into database, replacing the status checks and the deployment environments
+	//as they are not updated in their child tables
+	if err := s.saveVersion(&newConfig, oldConfig.Version, r.Repository.RequireStatusChecks != nil, r.Deployment.Environments != nil); err != nil {
+		return nil, err
+	}
+	return &newConfig, nil
+}
+
+//Patch partially updates a configuration.
+//The patch, a JSON Merge Patch or a JSON Patch according to the media type, is applied to the PutRequestPayload
+//of the current configuration and the result is updated as a PUT request would do.
+//The status checks and the environments are only replaced if the patch changed them, a null or empty list clears them.
+func (s *Configuration) Patch(id string, mediaType string, patch []byte, ifMatch string) (*models.Configuration, error) {
 
+	config, err := s.Get(id)
+
+	if err != nil {
+		return nil, err
 	}
 
-	//Save the new config into database
-	if err := s.SQL.Update(&newConfig); err != nil {
-		return nil, errors.New("error updating repository configuration")
+	current := config.ToPutRequestPayload()
+	doc, err := json.Marshal(current)
+
+	if err != nil {
+		return nil, errors.New("error encoding repository configuration")
 	}
-	return &newConfig, nil
+
+	var patched []byte
+	switch mediaType {
+	case jsonpatch.MediaTypeMergePatch:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case jsonpatch.MediaTypeJSONPatch:
+		patched, err = jsonpatch.Apply(doc, patch)
+	default:
+		return nil, ErrUnsupportedPatchType
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var r models.PutRequestPayload
+	if err := json.Unmarshal(patched, &r); err != nil {
+		return nil, jsonpatch.ErrInvalidPatch
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.Repository.Name = config.ID
+
+	//A removed or null list clears it, the PUT request semantics would leave it unchanged
+	if r.Repository.RequireStatusChecks == nil {
+		r.Repository.RequireStatusChecks = make([]string, 0)
+	}
+
+	if r.Deployment.Environments == nil {
+		r.Deployment.Environments = make([]models.EnvironmentPayload, 0)
+	}
+
+	if reflect.DeepEqual(r.Repository.RequireStatusChecks, current.Repository.RequireStatusChecks) {
+		r.Repository.RequireStatusChecks = nil
+	}
+
+	if reflect.DeepEqual(r.Deployment.Environments, current.Deployment.Environments) {
+		r.Deployment.Environments = nil
+	}
+
+	return s.Update(&r, ifMatch)
 }
 
 //Delete erase the configuration.
 //It makes a sof delete.
-//Receives the configuration id (repoName) and returns an error it it occurs.
-func (s *Configuration) Delete(id string) error {
+//Receives the configuration id (repoName) and the If-Match header with the version of the configuration the client saw.
+//Returns an error it it occurs.
+func (s *Configuration) Delete(id string, ifMatch string) error {
 
 	cf, err := s.Get(id)
 
@@ -126,13 +274,93 @@ func (s *Configuration) Delete(id string) error {
 		return err
 	}
 
+	if err := s.checkVersion(cf, ifMatch); err != nil {
+		return err
+	}
+
 	//Unset Workflow
 	//TODO: Desproteger de acuerdo al wf que tiene configurado
 
-	//Delete from configurations DB
-	if sqlErr := s.SQL.Delete(cf); sqlErr != nil {
+	//Remove the repository webhook
+	if unsetWebhookError := s.UnsetWebhook(cf); unsetWebhookError != nil {
+		return unsetWebhookError
+	}
+
+	//Delete the continuous integration job, it could be already deleted by hand
+	if deleteJobError := s.BuilderClient.DeleteJob(cf); deleteJobError != nil && deleteJobError != clients.ErrJobNotFound {
+		return deleteJobError
+	}
+
+	//Delete from configurations DB, as long as nobody changed it meanwhile
+	deleted, sqlErr := s.SQL.DeleteConfigurationVersion(cf, cf.Version)
+	if sqlErr != nil {
 		return sqlErr
 	}
+	if !deleted {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+//rollbackCreate removes the webhook and, if it was created, the continuous integration job of a configuration
+//which could not be created, so no configuration is left owning them and the creation can be retried.
+//The failures are only logged, the creation error is the one returned.
+func (s *Configuration) rollbackCreate(config *models.Configuration, jobCreated bool) {
+	if jobCreated {
+		if err := s.BuilderClient.DeleteJob(config); err != nil && err != clients.ErrJobNotFound {
+			log.Printf("error deleting the continuous integration job of %s: %v", *config.ID, err)
+		}
+	}
+
+	if err := s.UnsetWebhook(config); err != nil {
+		log.Printf("error deleting the webhook of %s: %v", *config.ID, err)
+	}
+}
+
+//checkVersion verifies the If-Match header matches the current version of the configuration.
+func (s *Configuration) checkVersion(config *models.Configuration, ifMatch string) error {
+	if ifMatch == "" {
+		return ErrPreconditionRequired
+	}
+	if !config.MatchesETag(ifMatch) {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
+//saveVersion saves the configuration into database as long as it still has the version it was read with,
+//so a concurrent change of the same version fails.
+func (s *Configuration) saveVersion(config *models.Configuration, version uint64, replaceChecks bool, replaceEnvironments bool) error {
+	updated, err := s.SQL.UpdateConfigurationVersion(config, version, replaceChecks, replaceEnvironments)
+	if err != nil {
+		return errors.New("error updating repository configuration")
+	}
+	if !updated {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
+//getConfiguration searches a configuration into database by its ID.
+//IDs without owner are the legacy ones, they are resolved by the repository name as long as
+//a single owner has a repository with that name.
+func getConfiguration(sql storage.SQLStorage, config *models.Configuration, id string) error {
+	err := sql.GetBy(config, "id = ?", id)
+
+	if err != gorm.ErrRecordNotFound || strings.Contains(id, "/") {
+		return err
+	}
+
+	var configs []models.Configuration
+	if err := sql.GetBy(&configs, "repository_name = ?", id); err != nil {
+		return err
+	}
+
+	if len(configs) != 1 {
+		return gorm.ErrRecordNotFound
+	}
 
+	*config = configs[0]
 	return nil
 }
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/herbal828/ci_cd-api/api/utils/coverage"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+//ErrInvalidCoverageReport is returned when a coverage report has no commit or no project coverage.
+var ErrInvalidCoverageReport = errors.New("invalid coverage report")
+
+//CoverageService is an interface which represents the CoverageService for testing purpose.
+type CoverageService interface {
+	Report(repoName string, report *models.CoverageReport) (*models.CoverageEvaluation, error)
+	Upload(repoName string, upload *models.CoverageUpload, data []byte) (*models.CoverageRecord, *models.CoverageEvaluation, error)
+	GetHistory(repoName string, branch string, from *time.Time, to *time.Time) (*models.CoverageHistory, error)
+}
+
+//Coverage represents the CoverageService layer
+//It has an instance of a DBClient layer and
+//A StatusPublisher instance
+type Coverage struct {
+	SQL             storage.SQLStorage
+	StatusPublisher StatusPublisher
+}
+
+//NewCoverageService initializes a CoverageService
+func NewCoverageService(sql storage.SQLStorage) *Coverage {
+	return &Coverage{
+		SQL:             sql,
+		StatusPublisher: NewStatusPublisher(),
+	}
+}
+
+//Report records the coverage of a commit, evaluates it against the repository CodeCoveragePullRequestThreshold
+//and CodeCoverageMaxDecrease and reports the result as the 'coverage' commit status.
+func (s *Coverage) Report(repoName string, report *models.CoverageReport) (*models.CoverageEvaluation, error) {
+
+	if report.Sha == "" || report.ProjectCoverage == nil {
+		return nil, ErrInvalidCoverageReport
+	}
+
+	config, err := s.getConfiguration(repoName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.getRecord(config, report.Sha)
+
+	if err != nil {
+		return nil, err
+	}
+
+	record.Branch = report.Branch
+	record.PullRequestNumber = report.PullRequestNumber
+	record.Format = report.Source
+	record.Coverage = *report.ProjectCoverage
+
+	if err := s.saveRecord(record); err != nil {
+		return nil, err
+	}
+
+	return s.evaluate(config, report)
+}
+
+//Upload parses a Go coverprofile, LCOV or Cobertura report, records the totals of the commit
+//and evaluates them against the repository CodeCoveragePullRequestThreshold and CodeCoverageMaxDecrease.
+//A new upload for the same commit replaces its previous totals.
+func (s *Coverage) Upload(repoName string, upload *models.CoverageUpload, data []byte) (*models.CoverageRecord, *models.CoverageEvaluation, error) {
+
+	if upload.Sha == "" {
+		return nil, nil, ErrInvalidCoverageReport
+	}
+
+	totals, parseErr := coverage.Parse(upload.Format, data)
+
+	if parseErr != nil {
+		return nil, nil, ErrInvalidCoverageReport
+	}
+
+	config, err := s.getConfiguration(repoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	format := upload.Format
+	if format == "" {
+		format = coverage.DetectFormat(data)
+	}
+
+	record, err := s.getRecord(config, upload.Sha)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record.Branch = upload.Branch
+	record.PullRequestNumber = upload.PullRequestNumber
+	record.Format = format
+	record.LinesCovered = totals.Covered
+	record.LinesValid = totals.Valid
+	record.Coverage = totals.Percentage()
+
+	if err := s.saveRecord(record); err != nil {
+		return nil, nil, err
+	}
+
+	report := record.ToCoverageReport()
+	report.BaseBranch = upload.BaseBranch
+
+	evaluation, err := s.evaluate(config, report)
+
+	if err != nil {
+		return record, nil, err
+	}
+
+	return record, evaluation, nil
+}
+
+//GetHistory returns the coverage time series of the repository branches.
+//The branch and the dates are optional filters.
+func (s *Coverage) GetHistory(repoName string, branch string, from *time.Time, to *time.Time) (*models.CoverageHistory, error) {
+
+	config, err := s.getConfiguration(repoName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	qry := "configuration_id = ?"
+	args := []interface{}{*config.ID}
+	if branch != "" {
+		qry += " AND branch = ?"
+		args = append(args, branch)
+	}
+	if from != nil {
+		qry += " AND created_at >= ?"
+		args = append(args, *from)
+	}
+	if to != nil {
+		qry += " AND created_at <= ?"
+		args = append(args, *to)
+	}
+
+	records := make([]models.CoverageRecord, 0)
+	if err := s.SQL.GetBy(&records, append([]interface{}{qry}, args...)...); err != nil {
+		return nil, errors.New("error getting coverage records")
+	}
+
+	return models.NewCoverageHistory(*config.ID, records), nil
+}
+
+//evaluate compares the reported coverage with the repository thresholds and with the latest coverage
+//of the base branch (the workflow default branch if the report does not have one), then it reports the
+//result as the 'coverage' commit status.
+func (s *Coverage) evaluate(config *models.Configuration, report *models.CoverageReport) (*models.CoverageEvaluation, error) {
+
+	if report.BaseBranch == "" {
+		report.BaseBranch = configs.GetWorkflowConfiguration(config).DefaultBranch
+	}
+
+	var baseCoverage *float64
+	if config.CodeCoverageMaxDecrease != nil && report.Branch != report.BaseBranch {
+		var records []models.CoverageRecord
+		if err := s.SQL.GetBy(&records, "configuration_id = ? AND branch = ?", *config.ID, report.BaseBranch); err != nil {
+			return nil, errors.New("error getting base branch coverage")
+		}
+
+		if base := models.GetLatestCoverageRecord(records, report.BaseBranch, report.Sha); base != nil {
+			baseCoverage = &base.Coverage
+		}
+	}
+
+	evaluation := report.Evaluate(config.CodeCoveragePullRequestThreshold, config.CodeCoverageMaxDecrease, baseCoverage)
+
+	update := models.StatusUpdate{
+		Context:     models.CoverageStatusContext,
+		State:       evaluation.State,
+		Description: evaluation.Description,
+		TargetURL:   report.TargetURL,
+	}
+
+	if err := s.StatusPublisher.PublishStatus(config, report.Sha, &update); err != nil {
+		return nil, err
+	}
+
+	return evaluation, nil
+}
+
+func (s *Coverage) getConfiguration(repoName string) (*models.Configuration, error) {
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+	return &config, nil
+}
+
+//getRecord returns the coverage recorded for a commit or a new record if there is none.
+func (s *Coverage) getRecord(config *models.Configuration, sha string) (*models.CoverageRecord, error) {
+	var record models.CoverageRecord
+	if err := s.SQL.GetBy(&record, "configuration_id = ? AND sha = ?", *config.ID, sha); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking coverage record existence")
+		}
+		return &models.CoverageRecord{
+			ConfigurationID: config.ID,
+			Sha:             sha,
+		}, nil
+	}
+	return &record, nil
+}
+
+func (s *Coverage) saveRecord(record *models.CoverageRecord) error {
+	if record.ID == nil {
+		if err := s.SQL.Insert(record); err != nil {
+			return errors.New("error saving coverage record")
+		}
+		return nil
+	}
+
+	if err := s.SQL.Update(record); err != nil {
+		return errors.New("error updating coverage record")
+	}
+	return nil
+}
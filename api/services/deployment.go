@@ -0,0 +1,328 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+	"log"
+)
+
+var (
+	//ErrEnvironmentNotFound is returned when a deployment targets an environment the configuration does not have.
+	ErrEnvironmentNotFound = errors.New("environment not found")
+
+	//ErrDeploymentInProgress is returned when an environment is deployed while another deployment is not finished yet.
+	ErrDeploymentInProgress = errors.New("there is a deployment in progress")
+
+	//ErrDeploymentFinished is returned when the progress of a finished deployment is reported.
+	ErrDeploymentFinished = errors.New("the deployment is already finished")
+
+	//ErrDeploymentAwaitingApproval is returned when the progress of a deployment which is not approved yet is reported.
+	ErrDeploymentAwaitingApproval = errors.New("the deployment is awaiting approval")
+
+	//ErrRollbackTargetNotFound is returned when an environment has no previous successful deployment to roll back to.
+	ErrRollbackTargetNotFound = errors.New("there is no previous successful deployment to roll back to")
+
+	//ErrInvalidDeploymentState is returned when a deployment status has an unknown state.
+	ErrInvalidDeploymentState = errors.New("invalid deployment state")
+)
+
+//DeploymentService is an interface which represents the DeploymentService for testing purpose.
+type DeploymentService interface {
+	Create(repoName string, r *models.PostDeploymentRequestPayload) (*models.Deployment, error)
+	Get(id uint64) (*models.Deployment, error)
+	AddStatus(id uint64, r *models.PostDeploymentStatusRequestPayload) (*models.Deployment, error)
+	HandleStatusEvent(payload *models.GithubDeploymentStatusPayload) (*models.Deployment, error)
+	Review(id uint64, token string, r *models.PostApprovalRequestPayload) (*models.Deployment, error)
+	Rollback(repoName string, envName string) (*models.Deployment, error)
+}
+
+//Deployment represents the DeploymentService layer
+//It has an instance of a DBClient layer,
+//A github client instance and
+//A constructor of the github clients which act on behalf of the approvers
+type Deployment struct {
+	SQL                 storage.SQLStorage
+	GithubClient        clients.GithubClient
+	NewUserGithubClient func(token string) clients.GithubClient
+}
+
+//NewDeploymentService initializes a DeploymentService
+func NewDeploymentService(sql storage.SQLStorage) *Deployment {
+	return &Deployment{
+		SQL:                 sql,
+		GithubClient:        clients.NewGithubClient(),
+		NewUserGithubClient: clients.NewGithubClientWithToken,
+	}
+}
+
+//Create deploys a ref into one of the repository environments.
+//The deployment is created on Github, where the CD backends pick it up, and it is recorded as pending.
+//Environments with a deployment target get the deployment delivered right away.
+//If the environment requires approvals, the deployment is recorded as awaiting them instead.
+func (s *Deployment) Create(repoName string, r *models.PostDeploymentRequestPayload) (*models.Deployment, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	env := config.GetEnvironment(r.Environment)
+	if env == nil {
+		return nil, ErrEnvironmentNotFound
+	}
+
+	ref := env.Ref
+	if r.Ref != nil && *r.Ref != "" {
+		ref = *r.Ref
+	}
+
+	if _, err := s.getEnvironmentDeployments(&config, env); err != nil {
+		return nil, err
+	}
+
+	deployment := models.NewDeployment(&config, env, ref)
+
+	//The deployment is recorded before it is created on Github, so every Github deployment is tracked
+	if err := s.SQL.Insert(deployment); err != nil {
+		return nil, errors.New("error saving deployment")
+	}
+
+	//The deployment is created on Github once it is approved
+	if deployment.State != models.DeploymentStateAwaitingApproval {
+		if err := s.launch(&config, env, deployment); err != nil {
+			return nil, err
+		}
+	}
+
+	return deployment, nil
+}
+
+//Rollback re-deploys into an environment the commit of its latest successful deployment which is not
+//the commit currently deployed. The rollback is recorded as a new deployment linked to the restored one,
+//and it awaits the approvals of the environment like any other deployment.
+func (s *Deployment) Rollback(repoName string, envName string) (*models.Deployment, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	env := config.GetEnvironment(envName)
+	if env == nil {
+		return nil, ErrEnvironmentNotFound
+	}
+
+	deployments, err := s.getEnvironmentDeployments(&config, env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	target := models.GetRollbackTarget(deployments)
+	if target == nil {
+		return nil, ErrRollbackTargetNotFound
+	}
+
+	deployment := models.NewRollbackDeployment(&config, env, target)
+
+	if err := s.SQL.Insert(deployment); err != nil {
+		return nil, errors.New("error saving deployment")
+	}
+
+	//The deployment is created on Github once it is approved
+	if deployment.State != models.DeploymentStateAwaitingApproval {
+		if err := s.launch(&config, env, deployment); err != nil {
+			return nil, err
+		}
+	}
+
+	return deployment, nil
+}
+
+//Get searches a deployment with its status history into database.
+func (s *Deployment) Get(id uint64) (*models.Deployment, error) {
+	var deployment models.Deployment
+	if err := s.SQL.GetBy(&deployment, "id = ?", id); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error getting deployment")
+		}
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+//AddStatus records the progress of a deployment reported by a CD backend and mirrors it on Github.
+func (s *Deployment) AddStatus(id uint64, r *models.PostDeploymentStatusRequestPayload) (*models.Deployment, error) {
+
+	if !models.IsValidDeploymentState(r.State) {
+		return nil, ErrInvalidDeploymentState
+	}
+
+	deployment, err := s.Get(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deployment.IsFinished() {
+		return nil, ErrDeploymentFinished
+	}
+
+	if deployment.State == models.DeploymentStateAwaitingApproval {
+		return nil, ErrDeploymentAwaitingApproval
+	}
+
+	var config models.Configuration
+	if err := s.SQL.GetBy(&config, "id = ?", *deployment.ConfigurationID); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	if err := s.recordStatus(&config, deployment, r.State, r.Description, r.LogURL); err != nil {
+		return nil, err
+	}
+
+	return deployment, nil
+}
+
+//HandleStatusEvent records the progress of a deployment reported directly on Github through a deployment_status event.
+//Events of deployments not created by this API, of deployments which do not belong to the event repository
+//and statuses already recorded are ignored.
+func (s *Deployment) HandleStatusEvent(payload *models.GithubDeploymentStatusPayload) (*models.Deployment, error) {
+
+	if payload.Repository.FullName == "" {
+		return nil, nil
+	}
+
+	var deployment models.Deployment
+	if err := s.SQL.GetBy(&deployment, "github_deployment_id = ? AND configuration_id = ?", payload.Deployment.ID, payload.Repository.FullName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error getting deployment")
+		}
+		return nil, nil
+	}
+
+	state := payload.DeploymentStatus.State
+	if deployment.State == state || deployment.IsFinished() || !models.IsValidDeploymentState(state) {
+		return &deployment, nil
+	}
+
+	logURL := payload.DeploymentStatus.LogURL
+	if logURL == "" {
+		logURL = payload.DeploymentStatus.TargetURL
+	}
+
+	deployment.AddStatus(state, payload.DeploymentStatus.Description, logURL)
+
+	if err := s.SQL.Update(&deployment); err != nil {
+		return nil, errors.New("error updating deployment")
+	}
+
+	return &deployment, nil
+}
+
+//start creates the Github deployment of a deployment, from then on the CD backends can pick it up.
+func (s *Deployment) start(config *models.Configuration, deployment *models.Deployment) error {
+	ref := deployment.GetDeployRef()
+	gd, err := s.GithubClient.CreateDeployment(config, ref, deployment.Environment, "Deployment of "+ref+" into "+deployment.Environment)
+
+	if err != nil {
+		return err
+	}
+
+	deployment.Start(gd)
+
+	return nil
+}
+
+//launch starts a recorded deployment and hands it to the target of the environment.
+//A deployment which can not be started is finished with an error, so it does not block the environment.
+func (s *Deployment) launch(config *models.Configuration, env *models.Environment, deployment *models.Deployment) error {
+	if err := s.start(config, deployment); err != nil {
+		if recordErr := s.recordStatus(config, deployment, models.DeploymentStateError, err.Error(), ""); recordErr != nil {
+			return recordErr
+		}
+		return err
+	}
+
+	if err := s.SQL.Update(deployment); err != nil {
+		return errors.New("error updating deployment")
+	}
+
+	return s.deliver(config, env, deployment)
+}
+
+//getEnvironmentDeployments returns the deployments of an environment.
+//An environment is deployed once at a time, so it fails if any of them is not finished.
+func (s *Deployment) getEnvironmentDeployments(config *models.Configuration, env *models.Environment) ([]models.Deployment, error) {
+	var deployments []models.Deployment
+	if err := s.SQL.GetBy(&deployments, "configuration_id = ? AND environment = ?", *config.ID, env.Name); err != nil {
+		return nil, errors.New("error getting environment deployments")
+	}
+
+	for _, d := range deployments {
+		if !d.IsFinished() {
+			return nil, ErrDeploymentInProgress
+		}
+	}
+
+	return deployments, nil
+}
+
+//deliver hands the deployment to the target of the environment and records the result.
+//Deployments of environments without target are left to the CD backends listening to Github.
+func (s *Deployment) deliver(config *models.Configuration, env *models.Environment, deployment *models.Deployment) error {
+	target, err := clients.NewDeploymentTarget(env)
+
+	if err == nil && target == nil {
+		return nil
+	}
+
+	if err == nil {
+		err = target.Deploy(config, deployment)
+	}
+
+	//The failures of the target are part of the deployment history
+	if err != nil {
+		return s.recordStatus(config, deployment, models.DeploymentStateError, err.Error(), "")
+	}
+
+	return s.recordStatus(config, deployment, models.DeploymentStateInProgress, "Deployment delivered to the "+env.GetTargetType()+" target", "")
+}
+
+//recordStatus appends a status to the deployment history and mirrors it on its Github deployment.
+//The deployment is already recorded when the mirror fails, so the failure is logged and not returned.
+func (s *Deployment) recordStatus(config *models.Configuration, deployment *models.Deployment, state string, description string, logURL string) error {
+	deployment.AddStatus(state, description, logURL)
+
+	if err := s.SQL.Update(deployment); err != nil {
+		return errors.New("error updating deployment")
+	}
+
+	if deployment.GithubDeploymentID == nil {
+		return nil
+	}
+
+	status := models.GithubDeploymentStatus{
+		State:       state,
+		LogURL:      logURL,
+		Description: description,
+	}
+
+	if err := s.GithubClient.CreateDeploymentStatus(config, *deployment.GithubDeploymentID, &status); err != nil {
+		log.Printf("error mirroring deployment %d %s status of %s: %v", *deployment.ID, state, *config.ID, err)
+	}
+
+	return nil
+}
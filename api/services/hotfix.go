@@ -0,0 +1,236 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+	"log"
+)
+
+var (
+	//ErrHotfixInProgress is returned when a hotfix is started while another one is not finished yet.
+	ErrHotfixInProgress = errors.New("there is a hotfix in progress")
+
+	//ErrVersionTagNotFound is returned when a repository has no semantic version tag to start a hotfix from.
+	ErrVersionTagNotFound = errors.New("there is no version tag to start the hotfix from")
+)
+
+//HotfixService is an interface which represents the HotfixService for testing purpose.
+type HotfixService interface {
+	Create(repoName string) (*models.Hotfix, error)
+	Get(repoName string, version string) (*models.Hotfix, error)
+	Finish(repoName string, version string) (*models.Hotfix, error)
+}
+
+//Hotfix represents the HotfixService layer
+//It has an instance of a DBClient layer and
+//A github client instance
+type Hotfix struct {
+	SQL          storage.SQLStorage
+	GithubClient clients.GithubClient
+}
+
+//NewHotfixService initializes a HotfixService
+func NewHotfixService(sql storage.SQLStorage) *Hotfix {
+	return &Hotfix{
+		SQL:          sql,
+		GithubClient: clients.NewGithubClient(),
+	}
+}
+
+//Create starts a new gitflow hotfix.
+//It creates the hotfix/x.y.z branch from the latest version tag, bumping its patch version,
+//and records the hotfix into database.
+func (s *Hotfix) Create(repoName string) (*models.Hotfix, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	var hotfixes []models.Hotfix
+	if err := s.SQL.GetBy(&hotfixes, "configuration_id = ?", *config.ID); err != nil {
+		return nil, errors.New("error getting repository hotfixes")
+	}
+
+	for _, hf := range hotfixes {
+		if hf.State != models.HotfixStateFinished {
+			return nil, ErrHotfixInProgress
+		}
+	}
+
+	tags, listTagsErr := s.GithubClient.ListTags(&config)
+
+	if listTagsErr != nil {
+		return nil, listTagsErr
+	}
+
+	latestTag, latest := models.GetLatestVersionTag(tags)
+
+	if latestTag == nil {
+		return nil, ErrVersionTagNotFound
+	}
+
+	version, _ := latest.Bump(models.BumpPatch)
+
+	hotfix := models.NewHotfix(&config, version, latestTag)
+
+	if createBranchErr := s.GithubClient.CreateBranch(&config, &models.Branch{Name: hotfix.Branch}, hotfix.BaseSha); createBranchErr != nil {
+		return nil, createBranchErr
+	}
+
+	//Save it into database
+	//The branch is removed when the hotfix is not saved, otherwise it would block the next hotfix
+	if err := s.SQL.Insert(hotfix); err != nil {
+		if deleteBranchErr := s.GithubClient.DeleteBranch(&config, hotfix.Branch); deleteBranchErr != nil {
+			log.Printf("error deleting hotfix branch %s of %s: %v", hotfix.Branch, *config.ID, deleteBranchErr)
+		}
+		return nil, errors.New("error saving new hotfix")
+	}
+
+	return hotfix, nil
+}
+
+//Get searches a hotfix of a repository into database.
+//Returns an error if the hotfix is not found.
+func (s *Hotfix) Get(repoName string, version string) (*models.Hotfix, error) {
+	var hotfix models.Hotfix
+	if err := s.SQL.GetBy(&hotfix, "configuration_id = ? AND version = ?", repoName, version); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking hotfix existence")
+		}
+		return nil, err
+	}
+	return &hotfix, nil
+}
+
+//Finish completes a gitflow hotfix.
+//It merges the hotfix branch into master once the required status checks are green, tags the merge commit
+//and merges the hotfix branch into develop.
+//Every step is persisted as soon as it is done, so calling Finish again resumes the hotfix from its last state.
+//The pull request steps check Github first, so a step done on Github but not persisted is not done twice.
+//Returns ErrRequiredChecksPending along with the hotfix if a pull request can not be merged yet.
+func (s *Hotfix) Finish(repoName string, version string) (*models.Hotfix, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	hotfix, err := s.Get(*config.ID, version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for hotfix.State != models.HotfixStateFinished {
+		var stepErr error
+
+		switch hotfix.State {
+		case models.HotfixStateCreated:
+			stepErr = s.openMasterPullRequest(&config, hotfix)
+		case models.HotfixStateMasterPrOpened:
+			stepErr = s.mergeMasterPullRequest(&config, hotfix)
+		case models.HotfixStateMasterMerged:
+			stepErr = s.tagHotfix(&config, hotfix)
+		case models.HotfixStateTagged:
+			stepErr = s.openDevelopPullRequest(&config, hotfix)
+		case models.HotfixStateDevelopPrOpened:
+			stepErr = s.mergeDevelopPullRequest(&config, hotfix)
+		default:
+			stepErr = errors.New("invalid hotfix state " + hotfix.State)
+		}
+
+		if stepErr != nil {
+			return hotfix, stepErr
+		}
+
+		//Save the completed step into database
+		if err := s.SQL.Update(hotfix); err != nil {
+			return hotfix, errors.New("error updating hotfix")
+		}
+	}
+
+	return hotfix, nil
+}
+
+//openMasterPullRequest opens the pull request which merges the hotfix branch into master.
+func (s *Hotfix) openMasterPullRequest(config *models.Configuration, hotfix *models.Hotfix) error {
+	pr, err := openPullRequest(s.GithubClient, config, hotfix.Branch, "master", "Hotfix "+hotfix.Version)
+
+	if err != nil {
+		return err
+	}
+
+	hotfix.MasterPullRequestNumber = &pr.Number
+	hotfix.State = models.HotfixStateMasterPrOpened
+
+	return nil
+}
+
+//mergeMasterPullRequest merges the master pull request once all the required status checks are successful.
+func (s *Hotfix) mergeMasterPullRequest(config *models.Configuration, hotfix *models.Hotfix) error {
+	sha, err := mergePullRequest(s.GithubClient, config, *hotfix.MasterPullRequestNumber, hotfix.Branch, "Hotfix "+hotfix.Version)
+
+	if err != nil {
+		return err
+	}
+
+	hotfix.MasterMergeSha = sha
+	hotfix.State = models.HotfixStateMasterMerged
+
+	return nil
+}
+
+//tagHotfix creates the annotated tag of the hotfix on the master merge commit.
+func (s *Hotfix) tagHotfix(config *models.Configuration, hotfix *models.Hotfix) error {
+	tag, err := s.GithubClient.CreateTag(config, hotfix.Tag(), "Hotfix "+hotfix.Version, hotfix.MasterMergeSha)
+
+	if err != nil {
+		return err
+	}
+
+	hotfix.TagSha = tag.Sha
+	hotfix.State = models.HotfixStateTagged
+
+	return nil
+}
+
+//openDevelopPullRequest opens the pull request which merges the hotfix branch into the workflow default branch.
+func (s *Hotfix) openDevelopPullRequest(config *models.Configuration, hotfix *models.Hotfix) error {
+	wfc := configs.GetWorkflowConfiguration(config)
+
+	pr, err := openPullRequest(s.GithubClient, config, hotfix.Branch, wfc.DefaultBranch, "Hotfix "+hotfix.Version)
+
+	if err != nil {
+		return err
+	}
+
+	hotfix.DevelopPullRequestNumber = &pr.Number
+	hotfix.State = models.HotfixStateDevelopPrOpened
+
+	return nil
+}
+
+//mergeDevelopPullRequest merges the develop pull request once all the required status checks are successful.
+func (s *Hotfix) mergeDevelopPullRequest(config *models.Configuration, hotfix *models.Hotfix) error {
+	sha, err := mergePullRequest(s.GithubClient, config, *hotfix.DevelopPullRequestNumber, hotfix.Branch, "Hotfix "+hotfix.Version)
+
+	if err != nil {
+		return err
+	}
+
+	hotfix.DevelopMergeSha = sha
+	hotfix.State = models.HotfixStateFinished
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+	"net/http"
+	"time"
+)
+
+var (
+	//ErrIdempotencyKeyInProgress is returned when a request reuses the key of a request which is not completed yet.
+	ErrIdempotencyKeyInProgress = errors.New("a request with the same idempotency key is in progress")
+
+	//ErrIdempotencyKeyMismatch is returned when a request reuses the key of a different request.
+	ErrIdempotencyKeyMismatch = errors.New("the idempotency key was used by a different request")
+)
+
+//IdempotencyService is an interface which represents the IdempotencyService for testing purpose.
+type IdempotencyService interface {
+	Start(key string, method string, path string, body []byte) (*models.IdempotencyKey, error)
+	Complete(k *models.IdempotencyKey, status int, body []byte, header http.Header) error
+	Release(k *models.IdempotencyKey) error
+}
+
+//Idempotency represents the IdempotencyService layer
+//It has an instance of a DBClient layer
+type Idempotency struct {
+	SQL storage.SQLStorage
+}
+
+//NewIdempotencyService initializes an IdempotencyService
+func NewIdempotencyService(sql storage.SQLStorage) *Idempotency {
+	return &Idempotency{
+		SQL: sql,
+	}
+}
+
+//Start claims the key for a request.
+//It returns the key in progress when the request has to be processed, or the completed key when its
+//response has to be replayed. The key is unique in database, so only one of concurrent requests claims it.
+func (s *Idempotency) Start(key string, method string, path string, body []byte) (*models.IdempotencyKey, error) {
+
+	k := models.NewIdempotencyKey(key, method, path, body)
+
+	if err := s.SQL.Insert(k); err == nil {
+		return k, nil
+	}
+
+	//The key already exists or it was claimed by a concurrent request
+	var existing models.IdempotencyKey
+	if err := s.SQL.GetBy(&existing, "`key` = ?", key); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking idempotency key existence")
+		}
+		return nil, errors.New("error saving idempotency key")
+	}
+
+	//Concurrent requests could find the same expired key, only one of them takes it over
+	if existing.IsExpired(time.Now()) {
+		taken, err := s.SQL.TakeOverIdempotencyKey(&existing, k.Fingerprint)
+		if err != nil {
+			return nil, errors.New("error updating idempotency key")
+		}
+		if !taken {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return &existing, nil
+	}
+
+	if existing.Fingerprint != k.Fingerprint {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+
+	if !existing.IsCompleted() {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &existing, nil
+}
+
+//Complete stores the response of the request to be replayed on its retries.
+func (s *Idempotency) Complete(k *models.IdempotencyKey, status int, body []byte, header http.Header) error {
+	k.Complete(status, body, header)
+	if err := s.SQL.Update(k); err != nil {
+		return errors.New("error updating idempotency key")
+	}
+	return nil
+}
+
+//Release removes the key of a request which failed unexpectedly, so its retries are processed again.
+func (s *Idempotency) Release(k *models.IdempotencyKey) error {
+	if err := s.SQL.Delete(k); err != nil {
+		return errors.New("error deleting idempotency key")
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	//ErrReleaseInProgress is returned when a release is started while another one is not finished yet.
+	ErrReleaseInProgress = errors.New("there is a release in progress")
+
+	//ErrInvalidReleaseVersion is returned when the requested version or bump is not valid.
+	ErrInvalidReleaseVersion = errors.New("invalid release version")
+
+	//ErrRequiredChecksPending is returned when a pull request can not be merged yet because
+	//some of the required status checks are not successful.
+	ErrRequiredChecksPending = errors.New("required status checks are not successful yet")
+)
+
+//ReleaseService is an interface which represents the ReleaseService for testing purpose.
+type ReleaseService interface {
+	Create(repoName string, r *models.PostReleaseRequestPayload) (*models.Release, error)
+	Get(repoName string, version string) (*models.Release, error)
+	Finish(repoName string, version string) (*models.Release, error)
+}
+
+//Release represents the ReleaseService layer
+//It has an instance of a DBClient layer and
+//A github client instance
+type Release struct {
+	SQL               storage.SQLStorage
+	GithubClient      clients.GithubClient
+	VersioningService VersioningService
+	ChangelogService  ChangelogService
+}
+
+//NewReleaseService initializes a ReleaseService
+func NewReleaseService(sql storage.SQLStorage) *Release {
+	return &Release{
+		SQL:               sql,
+		GithubClient:      clients.NewGithubClient(),
+		VersioningService: NewVersioningService(sql),
+		ChangelogService:  NewChangelogService(sql),
+	}
+}
+
+//Create starts a new gitflow release.
+//It computes the next semantic version, creates the release/x.y.z branch from the workflow
+//default branch and records the release into database.
+func (s *Release) Create(repoName string, r *models.PostReleaseRequestPayload) (*models.Release, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	var releases []models.Release
+	if err := s.SQL.GetBy(&releases, "configuration_id = ?", *config.ID); err != nil {
+		return nil, errors.New("error getting repository releases")
+	}
+
+	//Gitflow allows only one release at a time
+	latest := models.Version{}
+	for _, rl := range releases {
+		if rl.State != models.ReleaseStateFinished {
+			return nil, ErrReleaseInProgress
+		}
+		if v, err := models.ParseVersion(rl.Version); err == nil && latest.LessThan(*v) {
+			latest = *v
+		}
+	}
+
+	version, versionErr := s.nextReleaseVersion(&config, latest, r)
+
+	if versionErr != nil {
+		return nil, versionErr
+	}
+
+	//The release branch starts from the head of the workflow default branch
+	wfc := configs.GetWorkflowConfiguration(&config)
+
+	branchInfo, getBranchErr := s.GithubClient.GetBranchInformation(&config, wfc.DefaultBranch)
+
+	if getBranchErr != nil {
+		return nil, getBranchErr
+	}
+
+	release := models.NewRelease(&config, version, branchInfo.Commit.Sha)
+
+	if createBranchErr := s.GithubClient.CreateBranch(&config, &models.Branch{Name: release.Branch}, release.BaseSha); createBranchErr != nil {
+		return nil, createBranchErr
+	}
+
+	//Save it into database
+	if err := s.SQL.Insert(release); err != nil {
+		return nil, errors.New("error saving new release")
+	}
+
+	return release, nil
+}
+
+//Get searches a release of a repository into database.
+//Returns an error if the release is not found.
+func (s *Release) Get(repoName string, version string) (*models.Release, error) {
+	var release models.Release
+	if err := s.SQL.GetBy(&release, "configuration_id = ? AND version = ?", repoName, version); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking release existence")
+		}
+		return nil, err
+	}
+	return &release, nil
+}
+
+//Finish completes a gitflow release.
+//It opens the release pull request into master, merges it once the required status checks are green,
+//tags the merge commit, publishes the Github release and opens the back-merge pull request into develop.
+//Every step is persisted as soon as it is done, so calling Finish again resumes the release from its last state.
+//The pull request steps check Github first, so a step done on Github but not persisted is not done twice.
+//Returns ErrRequiredChecksPending along with the release if the pull request can not be merged yet.
+func (s *Release) Finish(repoName string, version string) (*models.Release, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	release, err := s.Get(*config.ID, version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for release.State != models.ReleaseStateFinished {
+		var stepErr error
+
+		switch release.State {
+		case models.ReleaseStateCreated:
+			stepErr = s.openReleasePullRequest(&config, release)
+		case models.ReleaseStatePrOpened:
+			stepErr = s.mergeReleasePullRequest(&config, release)
+		case models.ReleaseStateMerged:
+			stepErr = s.tagRelease(&config, release)
+		case models.ReleaseStateTagged:
+			stepErr = s.publishRelease(&config, release)
+		case models.ReleaseStatePublished:
+			stepErr = s.openBackMergePullRequest(&config, release)
+		default:
+			stepErr = errors.New("invalid release state " + release.State)
+		}
+
+		if stepErr != nil {
+			return release, stepErr
+		}
+
+		//Save the completed step into database
+		if err := s.SQL.Update(release); err != nil {
+			return release, errors.New("error updating release")
+		}
+	}
+
+	return release, nil
+}
+
+//openReleasePullRequest opens the pull request which merges the release branch into master.
+func (s *Release) openReleasePullRequest(config *models.Configuration, release *models.Release) error {
+	pr, err := openPullRequest(s.GithubClient, config, release.Branch, "master", "Release "+release.Version)
+
+	if err != nil {
+		return err
+	}
+
+	release.PullRequestNumber = &pr.Number
+	release.State = models.ReleaseStatePrOpened
+
+	return nil
+}
+
+//mergeReleasePullRequest merges the release pull request once all the required status checks are successful.
+func (s *Release) mergeReleasePullRequest(config *models.Configuration, release *models.Release) error {
+	sha, err := mergePullRequest(s.GithubClient, config, *release.PullRequestNumber, release.Branch, "Release "+release.Version)
+
+	if err != nil {
+		return err
+	}
+
+	release.MergeSha = sha
+	release.State = models.ReleaseStateMerged
+
+	return nil
+}
+
+//tagRelease creates the annotated tag of the release on the master merge commit.
+func (s *Release) tagRelease(config *models.Configuration, release *models.Release) error {
+	tag, err := s.GithubClient.CreateTag(config, release.Tag(), "Release "+release.Version, release.MergeSha)
+
+	if err != nil {
+		return err
+	}
+
+	release.TagSha = tag.Sha
+	release.State = models.ReleaseStateTagged
+
+	return nil
+}
+
+//publishRelease creates the Github release for the release tag, using its changelog as release notes.
+func (s *Release) publishRelease(config *models.Configuration, release *models.Release) error {
+	changelog, err := s.ChangelogService.Generate(config, release)
+
+	if err != nil {
+		return err
+	}
+
+	ghRelease, err := s.GithubClient.CreateRelease(config, release.Tag(), release.Version, changelog.Markdown())
+
+	if err != nil {
+		return err
+	}
+
+	release.GithubReleaseID = &ghRelease.ID
+	release.State = models.ReleaseStatePublished
+
+	return nil
+}
+
+//openBackMergePullRequest opens the pull request which merges the release changes back into develop.
+func (s *Release) openBackMergePullRequest(config *models.Configuration, release *models.Release) error {
+	wfc := configs.GetWorkflowConfiguration(config)
+
+	pr, err := openPullRequest(s.GithubClient, config, release.Branch, wfc.DefaultBranch, "Back-merge release "+release.Version)
+
+	if err != nil {
+		return err
+	}
+
+	release.BackMergePullRequestNumber = &pr.Number
+	release.State = models.ReleaseStateFinished
+
+	return nil
+}
+
+//openPullRequest opens a pull request to merge the head branch into the base one.
+//The open pull request between the same branches is reused, so a retried step does not fail on Github.
+func openPullRequest(gh clients.GithubClient, config *models.Configuration, head string, base string, title string) (*models.PullRequest, error) {
+	pr, err := gh.FindPullRequest(config, head, base)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if pr != nil {
+		return pr, nil
+	}
+
+	return gh.CreatePullRequest(config, head, base, title, "")
+}
+
+//mergePullRequest merges a pull request once all the required status checks of its head branch are successful.
+//A pull request already merged is not merged again, so a retried step does not fail on Github.
+//Returns the merge commit sha.
+func mergePullRequest(gh clients.GithubClient, config *models.Configuration, number int, head string, title string) (string, error) {
+	pr, err := gh.GetPullRequest(config, number)
+
+	if err != nil {
+		return "", err
+	}
+
+	if pr.Merged {
+		return pr.MergeCommitSha, nil
+	}
+
+	status, err := gh.GetCombinedStatus(config, head)
+
+	if err != nil {
+		return "", err
+	}
+
+	if pending := status.GetPendingContexts(config.GetRequiredStatusCheck()); len(pending) > 0 {
+		return "", ErrRequiredChecksPending
+	}
+
+	merge, err := gh.MergePullRequest(config, number, title)
+
+	if err != nil {
+		return "", err
+	}
+
+	return merge.Sha, nil
+}
+
+//nextReleaseVersion computes the version of a new release based on the latest released one.
+//An explicit version in the payload must be greater than the latest one. Otherwise the requested bump is applied
+//or, if there is none, the bump computed from the conventional commits of the default branch (patch if the commits
+//do not require a new version).
+func (s *Release) nextReleaseVersion(config *models.Configuration, latest models.Version, r *models.PostReleaseRequestPayload) (*models.Version, error) {
+	if r.Version != nil {
+		version, err := models.ParseVersion(*r.Version)
+		if err != nil || !latest.LessThan(*version) {
+			return nil, ErrInvalidReleaseVersion
+		}
+		return version, nil
+	}
+
+	var bump string
+	if r.Bump != nil {
+		bump = *r.Bump
+	} else {
+		nv, err := s.VersioningService.GetNextVersion(*config.ID, "")
+		if err != nil {
+			return nil, err
+		}
+
+		bump = nv.Bump
+		if bump == models.BumpNone {
+			bump = models.BumpPatch
+		}
+
+		//The latest tag wins over the recorded releases, it could be created by a hotfix
+		if current, err := models.ParseVersion(nv.Current); err == nil && latest.LessThan(*current) {
+			latest = *current
+		}
+	}
+
+	version, err := latest.Bump(bump)
+	if err != nil {
+		return nil, ErrInvalidReleaseVersion
+	}
+
+	return version, nil
+}
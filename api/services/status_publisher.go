@@ -0,0 +1,75 @@
+package services
+
+import (
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"time"
+)
+
+//StatusPublisher is an interface which represents the StatusPublisher for testing purpose.
+type StatusPublisher interface {
+	PublishStatus(config *models.Configuration, sha string, update *models.StatusUpdate) error
+	PublishCheckRun(config *models.Configuration, sha string, update *models.StatusUpdate) (*models.CheckRun, error)
+}
+
+//Publisher represents the StatusPublisher layer
+//It has an instance of a github client.
+type Publisher struct {
+	GithubClient clients.GithubClient
+}
+
+//NewStatusPublisher initializes a StatusPublisher
+func NewStatusPublisher() *Publisher {
+	return &Publisher{
+		GithubClient: clients.NewGithubClient(),
+	}
+}
+
+//PublishStatus reports the update as a commit status.
+//If the commit already has the same status for the update context, nothing is reported.
+func (p *Publisher) PublishStatus(config *models.Configuration, sha string, update *models.StatusUpdate) error {
+	combined, err := p.GithubClient.GetCombinedStatus(config, sha)
+
+	if err != nil {
+		return err
+	}
+
+	if update.IsPublished(combined) {
+		return nil
+	}
+
+	return p.GithubClient.CreateStatus(config, sha, update.ToCommitStatus())
+}
+
+//PublishCheckRun reports the update as a check run named after the update context.
+//The existing check run of the commit is updated in place, and it is left untouched when
+//it already has the same status, conclusion and output.
+func (p *Publisher) PublishCheckRun(config *models.Configuration, sha string, update *models.StatusUpdate) (*models.CheckRun, error) {
+	run := update.ToCheckRun(sha)
+
+	if run.Status == models.CheckRunStatusCompleted {
+		now := time.Now().UTC()
+		run.CompletedAt = &now
+	}
+
+	runs, err := p.GithubClient.ListCheckRuns(config, sha, update.Context)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(runs) == 0 || runs[0].ID == nil {
+		return p.GithubClient.CreateCheckRun(config, run)
+	}
+
+	//Check runs are listed in reverse chronological order, the first one is the current one
+	current := runs[0]
+	if current.Matches(run) {
+		return &current, nil
+	}
+
+	//The commit of a check run can not be changed
+	run.HeadSha = ""
+
+	return p.GithubClient.UpdateCheckRun(config, *current.ID, run)
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"log"
+)
+
+//configurationChildTables are the tables which reference a configuration by its ID.
+var configurationChildTables = []string{
+	"require_status_checks",
+	"branch_violations",
+	"releases",
+	"hotfixes",
+	"coverage_records",
+	"builds",
+	"environments",
+	"deployments",
+}
+
+//MigrateConfigurationIDs moves the configurations identified by the bare repository name
+//to the owner/name IDs, along with the rows of their child tables.
+//Each configuration is migrated in its own transaction, so it can be run many times.
+//A configuration which can not be migrated, e.g. because its new ID was already created, is logged and skipped.
+func (s *SQL) MigrateConfigurationIDs() error {
+	var configs []models.Configuration
+	if err := s.Client.Find(&configs, "id NOT LIKE ?", "%/%").Error; err != nil {
+		return err
+	}
+
+	for _, c := range configs {
+		if c.RepositoryOwner == nil || c.RepositoryName == nil {
+			continue
+		}
+
+		newID := models.GetConfigurationID(*c.RepositoryOwner, *c.RepositoryName)
+
+		if err := s.migrateConfigurationID(*c.ID, newID); err != nil {
+			log.Printf("error migrating configuration %s to %s: %v", *c.ID, newID, err)
+		}
+	}
+
+	return nil
+}
+
+//migrateConfigurationID moves a configuration and the rows of its child tables to the new ID in a transaction.
+func (s *SQL) migrateConfigurationID(oldID string, newID string) error {
+	tx := s.Client.Begin()
+	if err := tx.Exec("UPDATE configurations SET id = ? WHERE id = ?", newID, oldID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, table := range configurationChildTables {
+		if err := tx.Exec(fmt.Sprintf("UPDATE %s SET configuration_id = ? WHERE configuration_id = ?", table), newID, oldID).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/herbal828/ci_cd-api/api/configs"
 	"github.com/herbal828/ci_cd-api/api/models"
+	"time"
 
 	"github.com/jinzhu/gorm"
 )
@@ -14,8 +15,12 @@ type SQLStorage interface {
 	Update(interface{}) error
 	Get(interface{}, interface{}) error
 	GetBy(interface{}, ...interface{}) error
+	GetPage(e interface{}, order string, limit int, qry ...interface{}) error
 	Delete(interface{}) error
 	DeleteFromRequireStatusChecksByConfigurationID(*string) error
+	UpdateConfigurationVersion(config *models.Configuration, version uint64, replaceChecks bool, replaceEnvironments bool) (bool, error)
+	DeleteConfigurationVersion(config *models.Configuration, version uint64) (bool, error)
+	TakeOverIdempotencyKey(k *models.IdempotencyKey, fingerprint string) (bool, error)
 }
 
 //SQLClient is an interface built to represent a *gorm.DB instance generated by GORM
@@ -27,6 +32,8 @@ type SQLClient interface {
 	Set(name string, value interface{}) *gorm.DB
 	Close() error
 	AutoMigrate(values ...interface{}) *gorm.DB
+	Begin() *gorm.DB
+	Model(value interface{}) *gorm.DB
 }
 
 //SQL implements the SQLStorage interface
@@ -87,6 +94,18 @@ func (s *SQL) GetBy(e interface{}, qry ...interface{}) error {
 	return nil
 }
 
+//GetPage searches a page of elements into the database based on the given query, sorted by the given order
+func (s *SQL) GetPage(e interface{}, order string, limit int, qry ...interface{}) error {
+	//An empty condition selects every element
+	if len(qry) > 0 && qry[0] == "" {
+		qry = nil
+	}
+	if err := s.Client.Set("gorm:auto_preload", true).Order(order).Limit(limit).Find(e, qry...).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 //Update saves an interface into the database
 func (s *SQL) Update(e interface{}) error {
 	if err := s.Client.Save(e).Error; err != nil {
@@ -108,3 +127,84 @@ func (s *SQL) DeleteFromRequireStatusChecksByConfigurationID(id *string) error {
 	}
 	return nil
 }
+
+//UpdateConfigurationVersion saves a configuration only if it still has the given version into database.
+//The status checks and the environments are not updated in their child tables, so they are replaced when asked to.
+//Everything happens in a transaction, it returns false and changes nothing when the configuration was changed by another request.
+func (s *SQL) UpdateConfigurationVersion(config *models.Configuration, version uint64, replaceChecks bool, replaceEnvironments bool) (bool, error) {
+	tx := s.Client.Begin()
+
+	db := tx.Model(&models.Configuration{}).Where("id = ? AND version = ?", config.ID, version).UpdateColumn("version", config.Version)
+	if db.Error != nil {
+		tx.Rollback()
+		return false, db.Error
+	}
+	if db.RowsAffected != 1 {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if replaceChecks {
+		if err := tx.Delete(models.RequireStatusCheck{}, "configuration_id = ?", config.ID).Error; err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if replaceEnvironments {
+		if err := tx.Delete(models.EnvironmentApprover{}, "environment_id IN (SELECT id FROM environments WHERE configuration_id = ?)", config.ID).Error; err != nil {
+			tx.Rollback()
+			return false, err
+		}
+		if err := tx.Delete(models.Environment{}, "configuration_id = ?", config.ID).Error; err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err := tx.Save(config).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit().Error
+}
+
+//DeleteConfigurationVersion deletes a configuration only if it still has the given version into database.
+//It returns false when the configuration was changed by another request.
+func (s *SQL) DeleteConfigurationVersion(config *models.Configuration, version uint64) (bool, error) {
+	db := s.Client.Delete(&models.Configuration{}, "id = ? AND version = ?", config.ID, version)
+	if db.Error != nil {
+		return false, db.Error
+	}
+	return db.RowsAffected == 1, nil
+}
+
+//TakeOverIdempotencyKey claims an expired key for a new request only if it still has the state and the
+//update time it was read with, so only one of the concurrent requests takes it over.
+//It returns false and changes nothing when the key was changed by another request.
+func (s *SQL) TakeOverIdempotencyKey(k *models.IdempotencyKey, fingerprint string) (bool, error) {
+	now := time.Now()
+	db := s.Client.Model(&models.IdempotencyKey{}).Where("id = ? AND state = ? AND updated_at = ?", k.ID, k.State, k.UpdatedAt).UpdateColumns(map[string]interface{}{
+		"fingerprint":      fingerprint,
+		"state":            models.IdempotencyKeyStateInProgress,
+		"response_status":  0,
+		"response_body":    "",
+		"response_headers": "",
+		"updated_at":       now,
+	})
+	if db.Error != nil {
+		return false, db.Error
+	}
+	if db.RowsAffected != 1 {
+		return false, nil
+	}
+
+	k.Fingerprint = fingerprint
+	k.State = models.IdempotencyKeyStateInProgress
+	k.ResponseStatus = 0
+	k.ResponseBody = ""
+	k.ResponseHeaders = ""
+	k.UpdatedAt = now
+	return true, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/configs"
+	"github.com/herbal828/ci_cd-api/api/models"
+	"github.com/herbal828/ci_cd-api/api/services/storage"
+	"github.com/jinzhu/gorm"
+)
+
+//VersioningService is an interface which represents the VersioningService for testing purpose.
+type VersioningService interface {
+	GetNextVersion(repoName string, branch string) (*models.NextVersion, error)
+}
+
+//Versioning represents the VersioningService layer
+//It has an instance of a DBClient layer and
+//A github client instance
+type Versioning struct {
+	SQL          storage.SQLStorage
+	GithubClient clients.GithubClient
+}
+
+//NewVersioningService initializes a VersioningService
+func NewVersioningService(sql storage.SQLStorage) *Versioning {
+	return &Versioning{
+		SQL:          sql,
+		GithubClient: clients.NewGithubClient(),
+	}
+}
+
+//GetNextVersion computes the next semantic version of a repository branch.
+//It classifies the commits between the latest version tag and the branch head following the Conventional Commits
+//specification. If the branch is empty, the workflow default branch is used.
+//If the repository has no version tags, every commit of the branch is classified starting from 0.0.0.
+func (s *Versioning) GetNextVersion(repoName string, branch string) (*models.NextVersion, error) {
+
+	var config models.Configuration
+	if err := getConfiguration(s.SQL, &config, repoName); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.New("error checking configuration existence")
+		}
+		return nil, err
+	}
+
+	if branch == "" {
+		branch = configs.GetWorkflowConfiguration(&config).DefaultBranch
+	}
+
+	tags, listTagsErr := s.GithubClient.ListTags(&config)
+
+	if listTagsErr != nil {
+		return nil, listTagsErr
+	}
+
+	var commits []models.Commit
+	current := models.Version{}
+	baseTag := ""
+
+	if latestTag, latest := models.GetLatestVersionTag(tags); latestTag != nil {
+		compare, compareErr := s.GithubClient.CompareCommits(&config, latestTag.Name, branch)
+
+		if compareErr != nil {
+			return nil, compareErr
+		}
+
+		commits = compare.Commits
+		current = *latest
+		baseTag = latestTag.Name
+	} else {
+		branchCommits, listCommitsErr := s.GithubClient.ListCommits(&config, branch)
+
+		if listCommitsErr != nil {
+			return nil, listCommitsErr
+		}
+
+		commits = branchCommits
+	}
+
+	nv := models.CalculateNextVersion(current, commits)
+	nv.Branch = branch
+	nv.BaseTag = baseTag
+
+	return nv, nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"github.com/herbal828/ci_cd-api/api/clients"
+	"github.com/herbal828/ci_cd-api/api/models"
+)
+
+//SetWebhook makes sure the repository has a webhook reporting its events to this API.
+//If the configuration has no webhook or the registered one was removed from Github, a new one is created
+//and its id is stored into the configuration.
+func (c *Configuration) SetWebhook(config *models.Configuration) error {
+
+	if config.WebhookID != nil {
+		_, getHookErr := c.GithubClient.GetWebhook(config)
+
+		if getHookErr == nil {
+			return nil
+		}
+
+		//Any error different from a missing webhook can not be repaired
+		if getHookErr != clients.ErrWebhookNotFound {
+			return getHookErr
+		}
+	}
+
+	hook, createHookErr := c.GithubClient.CreateWebhook(config)
+
+	if createHookErr != nil {
+		return createHookErr
+	}
+
+	config.WebhookID = &hook.ID
+
+	return nil
+}
+
+//UnsetWebhook removes the repository webhook registered for the configuration.
+//A webhook already removed from Github is not considered an error.
+func (c *Configuration) UnsetWebhook(config *models.Configuration) error {
+
+	if config.WebhookID == nil {
+		return nil
+	}
+
+	deleteHookErr := c.GithubClient.DeleteWebhook(config)
+
+	if deleteHookErr != nil && deleteHookErr != clients.ErrWebhookNotFound {
+		return deleteHookErr
+	}
+
+	config.WebhookID = nil
+
+	return nil
+}
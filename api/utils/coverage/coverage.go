@@ -0,0 +1,231 @@
+package coverage
+
+// Parsers for the coverage reports generated by the most common tools.
+// All of them are reduced to the number of lines (or statements) covered and valid.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//Supported report formats
+const (
+	FormatGoCoverProfile = "gocoverprofile"
+	FormatLCOV           = "lcov"
+	FormatCobertura      = "cobertura"
+)
+
+//Totals represents the coverage totals of a report.
+type Totals struct {
+	Covered int64
+	Valid   int64
+}
+
+//Percentage returns the covered percentage, 0 if the report has nothing to cover.
+func (t *Totals) Percentage() float64 {
+	if t.Valid == 0 {
+		return 0
+	}
+	return float64(t.Covered) * 100 / float64(t.Valid)
+}
+
+//DetectFormat guesses the format of a report from its content.
+//Returns an empty string if the format is not recognized.
+func DetectFormat(data []byte) string {
+	content := bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(content, []byte("mode:")):
+		return FormatGoCoverProfile
+	case bytes.HasPrefix(content, []byte("<")) && bytes.Contains(content, []byte("<coverage")):
+		return FormatCobertura
+	case bytes.HasPrefix(content, []byte("TN:")) || bytes.HasPrefix(content, []byte("SF:")):
+		return FormatLCOV
+	default:
+		return ""
+	}
+}
+
+//Parse reads a report of the given format. If the format is empty, it is detected from the content.
+func Parse(format string, data []byte) (*Totals, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	switch strings.ToLower(format) {
+	case FormatGoCoverProfile, "go":
+		return ParseGoCoverProfile(data)
+	case FormatLCOV:
+		return ParseLCOV(data)
+	case FormatCobertura:
+		return ParseCobertura(data)
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported coverage format %s", format))
+	}
+}
+
+//ParseGoCoverProfile reads a profile generated by 'go test -coverprofile'.
+//Each block is counted by its number of statements, repeated blocks (merged profiles) are counted once.
+func ParseGoCoverProfile(data []byte) (*Totals, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "mode:") {
+		return nil, errors.New("invalid go coverprofile: missing mode line")
+	}
+
+	statements := make(map[string]int64)
+	covered := make(map[string]bool)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		//file.go:10.2,12.16 2 1
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, errors.New(fmt.Sprintf("invalid go coverprofile line: %s", line))
+		}
+
+		numStmt, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid go coverprofile line: %s", line))
+		}
+
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid go coverprofile line: %s", line))
+		}
+
+		statements[fields[0]] = numStmt
+		if count > 0 {
+			covered[fields[0]] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var totals Totals
+	for block, numStmt := range statements {
+		totals.Valid += numStmt
+		if covered[block] {
+			totals.Covered += numStmt
+		}
+	}
+
+	return &totals, nil
+}
+
+//ParseLCOV reads a tracefile in LCOV format.
+//The LF/LH summaries of each record are used, or its DA lines when the summaries are missing.
+func ParseLCOV(data []byte) (*Totals, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	var totals Totals
+	var found, hit, daFound, daHit int64
+	hasSummary := false
+	records := 0
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "LF:"):
+			n, err := strconv.ParseInt(strings.TrimPrefix(line, "LF:"), 10, 64)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid lcov line: %s", line))
+			}
+			found = n
+			hasSummary = true
+		case strings.HasPrefix(line, "LH:"):
+			n, err := strconv.ParseInt(strings.TrimPrefix(line, "LH:"), 10, 64)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid lcov line: %s", line))
+			}
+			hit = n
+			hasSummary = true
+		case strings.HasPrefix(line, "DA:"):
+			//DA:<line number>,<execution count>[,<checksum>]
+			parts := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(parts) < 2 {
+				return nil, errors.New(fmt.Sprintf("invalid lcov line: %s", line))
+			}
+			daFound++
+			if count, err := strconv.ParseInt(parts[1], 10, 64); err == nil && count > 0 {
+				daHit++
+			}
+		case line == "end_of_record":
+			if hasSummary {
+				totals.Valid += found
+				totals.Covered += hit
+			} else {
+				totals.Valid += daFound
+				totals.Covered += daHit
+			}
+			found, hit, daFound, daHit = 0, 0, 0, 0
+			hasSummary = false
+			records++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if records == 0 {
+		return nil, errors.New("invalid lcov report: no records found")
+	}
+
+	return &totals, nil
+}
+
+type coberturaReport struct {
+	XMLName      xml.Name `xml:"coverage"`
+	LinesCovered *int64   `xml:"lines-covered,attr"`
+	LinesValid   *int64   `xml:"lines-valid,attr"`
+	Packages     []struct {
+		Classes []struct {
+			Lines []struct {
+				Hits int64 `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+//ParseCobertura reads a Cobertura XML report.
+//The lines-covered/lines-valid attributes are used, or the class lines when the attributes are missing.
+func ParseCobertura(data []byte) (*Totals, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid cobertura report: %s", err.Error()))
+	}
+
+	if report.LinesCovered != nil && report.LinesValid != nil {
+		return &Totals{
+			Covered: *report.LinesCovered,
+			Valid:   *report.LinesValid,
+		}, nil
+	}
+
+	var totals Totals
+	for _, p := range report.Packages {
+		for _, c := range p.Classes {
+			for _, l := range c.Lines {
+				totals.Valid++
+				if l.Hits > 0 {
+					totals.Covered++
+				}
+			}
+		}
+	}
+
+	return &totals, nil
+}
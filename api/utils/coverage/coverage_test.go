@@ -0,0 +1,121 @@
+package coverage
+
+import (
+	"reflect"
+	"testing"
+)
+
+const goCoverProfile = `mode: set
+github.com/herbal828/ci_cd-api/api/models/version.go:27.51,30.22 2 1
+github.com/herbal828/ci_cd-api/api/models/version.go:34.2,34.30 3 0
+github.com/herbal828/ci_cd-api/api/models/version.go:27.51,30.22 2 1
+github.com/herbal828/ci_cd-api/api/models/commit.go:10.2,12.3 5 4
+`
+
+const lcovReport = `TN:
+SF:src/index.js
+DA:1,1
+DA:2,0
+LF:2
+LH:1
+end_of_record
+SF:src/util.js
+DA:1,3
+DA:2,1
+DA:3,0
+end_of_record
+`
+
+const coberturaWithTotals = `<?xml version="1.0" ?>
+<coverage line-rate="0.75" lines-covered="3" lines-valid="4" version="1.9">
+	<packages/>
+</coverage>
+`
+
+const coberturaWithoutTotals = `<?xml version="1.0" ?>
+<coverage line-rate="0.5">
+	<packages>
+		<package name="api">
+			<classes>
+				<class name="Api" filename="api.py">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		data    string
+		want    *Totals
+		wantErr bool
+	}{
+		{
+			name:   "go coverprofile",
+			format: FormatGoCoverProfile,
+			data:   goCoverProfile,
+			want:   &Totals{Covered: 7, Valid: 10},
+		},
+		{
+			name: "detected go coverprofile",
+			data: goCoverProfile,
+			want: &Totals{Covered: 7, Valid: 10},
+		},
+		{
+			name:   "lcov",
+			format: FormatLCOV,
+			data:   lcovReport,
+			want:   &Totals{Covered: 3, Valid: 5},
+		},
+		{
+			name: "detected cobertura",
+			data: coberturaWithTotals,
+			want: &Totals{Covered: 3, Valid: 4},
+		},
+		{
+			name:   "cobertura without totals",
+			format: FormatCobertura,
+			data:   coberturaWithoutTotals,
+			want:   &Totals{Covered: 1, Valid: 2},
+		},
+		{
+			name:    "unknown format",
+			data:    "coverage: 80%",
+			wantErr: true,
+		},
+		{
+			name:    "invalid go coverprofile",
+			format:  FormatGoCoverProfile,
+			data:    "mode: set\nversion.go:27.51,30.22 two 1\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.format, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTotals_Percentage(t *testing.T) {
+	if got := (&Totals{Covered: 3, Valid: 4}).Percentage(); got != 75 {
+		t.Errorf("Totals.Percentage() = %v, want 75", got)
+	}
+	if got := (&Totals{}).Percentage(); got != 0 {
+		t.Errorf("Totals.Percentage() = %v, want 0", got)
+	}
+}
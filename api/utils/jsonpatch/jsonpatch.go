@@ -0,0 +1,336 @@
+package jsonpatch
+
+// JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents applied over JSON documents.
+// The documents are decoded into maps and slices, patched and encoded again.
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//Media types of the patch documents
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	//ErrInvalidPatch is returned when a patch document is malformed or one of its operations can not be applied.
+	ErrInvalidPatch = errors.New("invalid patch document")
+
+	//ErrTestFailed is returned when a JSON Patch test operation does not match the document.
+	ErrTestFailed = errors.New("patch test operation failed")
+)
+
+//Operation is a JSON Patch operation.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+//MergePatch applies a JSON Merge Patch to a document.
+//Objects are merged recursively, null values remove members and any other value replaces the target one.
+func MergePatch(doc []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+//Apply applies the operations of a JSON Patch to a document.
+//The operations are applied in order and the patch is applied completely or not at all.
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	for _, op := range ops {
+		var err error
+		if target, err = apply(target, &op); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func apply(doc interface{}, op *Operation) (interface{}, error) {
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, ErrInvalidPatch
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, ErrInvalidPatch
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, op.Path, value)
+		case "replace":
+			doc, _, err := remove(doc, op.Path)
+			if err != nil {
+				return nil, err
+			}
+			return add(doc, op.Path, value)
+		default:
+			current, err := get(doc, op.Path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrTestFailed
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		doc, _, err := remove(doc, op.Path)
+		return doc, err
+
+	case "move":
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, ErrInvalidPatch
+		}
+		doc, value, err := remove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, value)
+
+	case "copy":
+		value, err := get(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, deepCopy(value))
+	}
+
+	return nil, ErrInvalidPatch
+}
+
+//parsePointer splits a JSON Pointer into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, ErrInvalidPatch
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+//arrayIndex parses the index of an array reference token, '-' is the index after the last element.
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, ErrInvalidPatch
+	}
+
+	max := length - 1
+	if allowEnd {
+		max = length
+	}
+	if i > max {
+		return 0, ErrInvalidPatch
+	}
+	return i, nil
+}
+
+func get(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, t := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			v, ok := node[t]
+			if !ok {
+				return nil, ErrInvalidPatch
+			}
+			current = v
+		case []interface{}:
+			i, err := arrayIndex(t, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[i]
+		default:
+			return nil, ErrInvalidPatch
+		}
+	}
+	return current, nil
+}
+
+//add sets the value at the pointer, inserting it when the parent is an array.
+//It returns the document, which is replaced when the pointer is the root.
+func add(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return update(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, ErrInvalidPatch
+	})
+}
+
+//remove deletes the value at the pointer and returns the document along with the removed value.
+func remove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+
+	var removed interface{}
+	doc, err = update(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			v, ok := node[token]
+			if !ok {
+				return nil, ErrInvalidPatch
+			}
+			removed = v
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i:i], node[i+1:]...), nil
+		}
+		return nil, ErrInvalidPatch
+	})
+
+	return doc, removed, err
+}
+
+//update walks the document to the parent of the last token and replaces it by the result of fn.
+//Arrays change their length, so every parent is stored again into its own parent and the new document is returned.
+func update(doc interface{}, tokens []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	parents := make([]interface{}, len(tokens))
+	parents[0] = doc
+	for i, t := range tokens[:len(tokens)-1] {
+		child, err := get(parents[i], "/"+escape(t))
+		if err != nil {
+			return nil, err
+		}
+		parents[i+1] = child
+	}
+
+	last := len(tokens) - 1
+	value, err := fn(parents[last], tokens[last])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := last - 1; i >= 0; i-- {
+		switch node := parents[i].(type) {
+		case map[string]interface{}:
+			node[tokens[i]] = value
+			value = node
+		case []interface{}:
+			index, _ := strconv.Atoi(tokens[i])
+			node[index] = value
+			value = node
+		}
+	}
+	return value, nil
+}
+
+func escape(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = deepCopy(e)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = deepCopy(e)
+		}
+		return c
+	}
+	return value
+}
@@ -0,0 +1,137 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const document = `{"repository":{"required_status_checks":["ci","lint"]},"code_coverage":{"pull_request_threshold":80,"max_decrease":null}}`
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "test - replace a nested field",
+			patch: `{"code_coverage":{"pull_request_threshold":90}}`,
+			want:  `{"repository":{"required_status_checks":["ci","lint"]},"code_coverage":{"pull_request_threshold":90,"max_decrease":null}}`,
+		},
+		{
+			name:  "test - null removes a member",
+			patch: `{"code_coverage":{"max_decrease":null}}`,
+			want:  `{"repository":{"required_status_checks":["ci","lint"]},"code_coverage":{"pull_request_threshold":80}}`,
+		},
+		{
+			name:  "test - arrays are replaced",
+			patch: `{"repository":{"required_status_checks":["ci"]}}`,
+			want:  `{"repository":{"required_status_checks":["ci"]},"code_coverage":{"pull_request_threshold":80,"max_decrease":null}}`,
+		},
+		{
+			name:  "test - new object member",
+			patch: `{"deployment":{"environments":[]}}`,
+			want:  `{"repository":{"required_status_checks":["ci","lint"]},"code_coverage":{"pull_request_threshold":80,"max_decrease":null},"deployment":{"environments":[]}}`,
+		},
+		{
+			name:    "test - malformed patch",
+			patch:   `{"code_coverage":`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(document), []byte(tt.patch))
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.JSONEq(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "test - append a status check",
+			patch: `[{"op":"add","path":"/repository/required_status_checks/-","value":"coverage"}]`,
+			want:  `{"repository":{"required_status_checks":["ci","lint","coverage"]},"code_coverage":{"pull_request_threshold":80,"max_decrease":null}}`,
+		},
+		{
+			name:  "test - insert a status check",
+			patch: `[{"op":"add","path":"/repository/required_status_checks/0","value":"coverage"}]`,
+			want:  `{"repository":{"required_status_checks":["coverage","ci","lint"]},"code_coverage":{"pull_request_threshold":80,"max_decrease":null}}`,
+		},
+		{
+			name:  "test - remove a status check",
+			patch: `[{"op":"test","path":"/repository/required_status_checks/1","value":"lint"},{"op":"remove","path":"/repository/required_status_checks/1"}]`,
+			want:  `{"repository":{"required_status_checks":["ci"]},"code_coverage":{"pull_request_threshold":80,"max_decrease":null}}`,
+		},
+		{
+			name:  "test - replace a field with null",
+			patch: `[{"op":"replace","path":"/code_coverage/pull_request_threshold","value":null}]`,
+			want:  `{"repository":{"required_status_checks":["ci","lint"]},"code_coverage":{"pull_request_threshold":null,"max_decrease":null}}`,
+		},
+		{
+			name:  "test - move and copy",
+			patch: `[{"op":"copy","from":"/code_coverage/pull_request_threshold","path":"/code_coverage/max_decrease"},{"op":"move","from":"/repository/required_status_checks","path":"/checks"}]`,
+			want:  `{"repository":{},"checks":["ci","lint"],"code_coverage":{"pull_request_threshold":80,"max_decrease":80}}`,
+		},
+		{
+			name:  "test - escaped path",
+			patch: `[{"op":"add","path":"/a~1b~0c","value":1}]`,
+			want:  `{"repository":{"required_status_checks":["ci","lint"]},"code_coverage":{"pull_request_threshold":80,"max_decrease":null},"a/b~c":1}`,
+		},
+		{
+			name:    "test - failed test operation",
+			patch:   `[{"op":"test","path":"/code_coverage/pull_request_threshold","value":70}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:    "test - missing path",
+			patch:   `[{"op":"remove","path":"/workflow/type"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "test - index out of bounds",
+			patch:   `[{"op":"add","path":"/repository/required_status_checks/3","value":"coverage"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "test - add without value",
+			patch:   `[{"op":"add","path":"/repository/required_status_checks/-"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "test - unknown operation",
+			patch:   `[{"op":"merge","path":"/repository"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "test - move into itself",
+			patch:   `[{"op":"move","from":"/repository","path":"/repository/inner"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "test - not an array of operations",
+			patch:   `{"op":"remove","path":"/repository"}`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(document), []byte(tt.patch))
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.JSONEq(t, tt.want, string(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+//SignatureSHA256 returns the HMAC-SHA256 of the body with the secret, in the 'sha256=<hex>' format used by Github.
+func SignatureSHA256(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//ValidSignatureSHA256 reports if the signature is the HMAC-SHA256 of the body with the secret.
+//Requests are never valid without secret, so an unconfigured secret does not accept unsigned requests.
+func ValidSignatureSHA256(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	return hmac.Equal([]byte(SignatureSHA256(secret, body)), []byte(signature))
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidSignatureSHA256(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/feature/login"}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		want      bool
+	}{
+		{
+			name:      "test - valid signature",
+			secret:    "s3cr3t",
+			signature: SignatureSHA256("s3cr3t", body),
+			want:      true,
+		},
+		{
+			name:      "test - signed with another secret",
+			secret:    "s3cr3t",
+			signature: SignatureSHA256("other", body),
+			want:      false,
+		},
+		{
+			name:      "test - missing signature",
+			secret:    "s3cr3t",
+			signature: "",
+			want:      false,
+		},
+		{
+			name:      "test - without sha256 prefix",
+			secret:    "s3cr3t",
+			signature: SignatureSHA256("s3cr3t", body)[len("sha256="):],
+			want:      false,
+		},
+		{
+			name:      "test - unconfigured secret",
+			secret:    "",
+			signature: SignatureSHA256("", body),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidSignatureSHA256(tt.secret, body, tt.signature))
+		})
+	}
+}